@@ -0,0 +1,216 @@
+package sqlparser
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// AvroOptions is the options of GenerateAvro, the zero value is usable
+type AvroOptions struct {
+	// TopicPrefix is the topic.prefix of the Debezium connector, the first part of the namespace
+	TopicPrefix string
+}
+
+// avroRecord is the record schema, the fields are in the order of Avro schema documents
+type avroRecord struct {
+	Type        string       `json:"type"`
+	Name        string       `json:"name"`
+	Namespace   string       `json:"namespace,omitempty"`
+	Fields      []*avroField `json:"fields"`
+	ConnectName string       `json:"connect.name,omitempty"`
+}
+
+type avroField struct {
+	Name    string          `json:"name"`
+	Type    interface{}     `json:"type"`
+	Default json.RawMessage `json:"default,omitempty"`
+}
+
+// avroType is the primitive type with the logical type and the properties of Kafka Connect
+type avroType struct {
+	Type              string            `json:"type"`
+	LogicalType       string            `json:"logicalType,omitempty"`
+	Precision         int               `json:"precision,omitempty"`
+	Scale             int               `json:"scale,omitempty"`
+	ConnectType       string            `json:"connect.type,omitempty"`
+	ConnectName       string            `json:"connect.name,omitempty"`
+	ConnectParameters map[string]string `json:"connect.parameters,omitempty"`
+}
+
+// GenerateAvro return the indented .avsc of the row of table, encoded like the Debezium MySQL
+// connector with the default modes: DECIMAL is bytes of logical type decimal, DATE is date,
+// TIME is time-micros, DATETIME is timestamp-millis or timestamp-micros by the fractional seconds
+// precision, TIMESTAMP is the zoned string, ENUM and SET are strings and BIT(n) is bytes. The
+// nullable columns are the unions with null. The record is named Value in the namespace
+// of prefix.schema.table like the value schema of Debezium
+func GenerateAvro(t *Table, opts *AvroOptions) ([]byte, error) {
+	var o AvroOptions
+	if opts != nil {
+		o = *opts
+	}
+	var parts []string
+	for _, part := range []string{o.TopicPrefix, t.Schema, t.Name} {
+		if part != "" {
+			parts = append(parts, avroName(part))
+		}
+	}
+	namespace := strings.Join(parts, ".")
+	record := &avroRecord{Type: "record", Name: "Value", Namespace: namespace, ConnectName: namespace + ".Value"}
+
+	defined := map[string]bool{}
+	for _, col := range t.Columns {
+		field := &avroField{Name: avroName(col.Name), Type: avroColumnType(col.DataType, defined)}
+		if columnNullable(t, col) {
+			field.Type = []interface{}{"null", field.Type}
+			field.Default = json.RawMessage("null")
+		} else if def := avroDefault(col, field.Type); def != nil {
+			field.Default = def
+		}
+		record.Fields = append(record.Fields, field)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(record); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// avroColumnType return the type of the not null value, the named types are defined once in defined
+func avroColumnType(dt *DataType, defined map[string]bool) interface{} {
+	if dt == nil {
+		return "string"
+	}
+	if dt.Name == "BOOL" || dt.Name == "BOOLEAN" {
+		return "boolean"
+	}
+	name := foreignKeyType(dt)
+	switch name {
+	case "TINYINT", "SMALLINT":
+		if name == "SMALLINT" && dt.IsUnsigned {
+			return "int"
+		}
+		return &avroType{Type: "int", ConnectType: "int16"}
+	case "MEDIUMINT":
+		return "int"
+	case "INT":
+		if dt.IsUnsigned {
+			return "long"
+		}
+		return "int"
+	case "BIGINT":
+		return "long"
+	case "FLOAT":
+		// FLOAT(p) of p over 23 is DOUBLE
+		if dt.HasLength && dt.Length > 23 {
+			return "double"
+		}
+		return "float"
+	case "DOUBLE":
+		return "double"
+	case "DECIMAL":
+		precision, scale := decimalPrecision(dt)
+		return &avroType{Type: "bytes", LogicalType: "decimal", Precision: precision, Scale: scale,
+			ConnectName:       "org.apache.kafka.connect.data.Decimal",
+			ConnectParameters: map[string]string{"scale": strconv.Itoa(scale), "connect.decimal.precision": strconv.Itoa(precision)}}
+	case "BIT":
+		length := 1
+		if dt.HasLength {
+			length = dt.Length
+		}
+		if length == 1 {
+			return "boolean"
+		}
+		return &avroType{Type: "bytes", ConnectName: "io.debezium.data.Bits",
+			ConnectParameters: map[string]string{"length": strconv.Itoa(length)}}
+	case "DATE":
+		return &avroType{Type: "int", LogicalType: "date", ConnectName: "io.debezium.time.Date"}
+	case "TIME":
+		return &avroType{Type: "long", LogicalType: "time-micros", ConnectName: "io.debezium.time.MicroTime"}
+	case "DATETIME":
+		if dt.HasLength && dt.Length > 3 {
+			return &avroType{Type: "long", LogicalType: "timestamp-micros", ConnectName: "io.debezium.time.MicroTimestamp"}
+		}
+		return &avroType{Type: "long", LogicalType: "timestamp-millis", ConnectName: "io.debezium.time.Timestamp"}
+	case "TIMESTAMP":
+		return &avroType{Type: "string", ConnectName: "io.debezium.time.ZonedTimestamp"}
+	case "YEAR":
+		return &avroType{Type: "int", ConnectName: "io.debezium.time.Year"}
+	case "ENUM", "SET":
+		connect := "io.debezium.data.Enum"
+		if name == "SET" {
+			connect = "io.debezium.data.EnumSet"
+		}
+		return &avroType{Type: "string", ConnectName: connect,
+			ConnectParameters: map[string]string{"allowed": strings.Join(dt.CollectionOptions, ",")}}
+	case "JSON":
+		return &avroType{Type: "string", ConnectName: "io.debezium.data.Json"}
+	case "GEOMETRY", "POINT", "LINESTRING", "POLYGON", "MULTIPOINT", "MULTILINESTRING", "MULTIPOLYGON",
+		"GEOMETRYCOLLECTION", "GEOMCOLLECTION":
+		const geometry = "io.debezium.data.geometry.Geometry"
+		if defined[geometry] {
+			return geometry
+		}
+		defined[geometry] = true
+		return &avroRecord{Type: "record", Name: "Geometry", Namespace: "io.debezium.data.geometry",
+			ConnectName: geometry, Fields: []*avroField{
+				{Name: "wkb", Type: "bytes"},
+				{Name: "srid", Type: []interface{}{"null", "int"}, Default: json.RawMessage("null")},
+			}}
+	}
+	switch typeKind(name) {
+	case "binary":
+		return "bytes"
+	}
+	return "string"
+}
+
+// avroDefault return the default of the NOT NULL column in JSON, nil if the default is not a
+// value of the int, long, float, double, boolean or string type, e.g. the temporal types
+func avroDefault(col *Column, typ interface{}) json.RawMessage {
+	cons := col.Constraint
+	if cons == nil || cons.DefaultValue == nil || !cons.DefaultValue.Is {
+		return nil
+	}
+	base, ok := typ.(string)
+	if t, isType := typ.(*avroType); isType && t.LogicalType == "" && t.ConnectName != "io.debezium.time.ZonedTimestamp" {
+		base, ok = t.Type, true
+	}
+	if !ok {
+		return nil
+	}
+	value := typedDefault(col.DataType, cons.DefaultValue.Value)
+	switch value.(type) {
+	case bool:
+		ok = base == "boolean"
+	case json.Number:
+		ok = base == "int" || base == "long" || base == "float" || base == "double"
+	case string:
+		ok = base == "string"
+	default:
+		ok = false
+	}
+	if !ok {
+		return nil
+	}
+	res, _ := json.Marshal(value)
+	return res
+}
+
+var avroInvalid = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// avroName replace the characters not allowed in Avro names with underscores,
+// like the avro field.name.adjustment.mode of Debezium
+func avroName(name string) string {
+	name = avroInvalid.ReplaceAllString(name, "_")
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
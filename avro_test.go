@@ -0,0 +1,148 @@
+package sqlparser
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGenerateAvro(t *testing.T) {
+	Convey("TestGenerateAvro", t, func() {
+		tables, err := From(`CREATE TABLE app.orders (
+  id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  qty smallint NOT NULL DEFAULT 1,
+  amount decimal(10,2) NOT NULL,
+  status enum('new','paid') NOT NULL DEFAULT 'new',
+  flags bit(8),
+  paid bit(1) NOT NULL,
+  note varchar(255),
+  created_at datetime(3) NOT NULL,
+  shipped_at datetime(6),
+  updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  ` + "`due-date`" + ` date
+);`)
+		So(err, ShouldBeNil)
+		res, err := GenerateAvro(tables[0], &AvroOptions{TopicPrefix: "shop"})
+		So(err, ShouldBeNil)
+		So(string(res), ShouldEqual, `{
+  "type": "record",
+  "name": "Value",
+  "namespace": "shop.app.orders",
+  "fields": [
+    {
+      "name": "id",
+      "type": "long"
+    },
+    {
+      "name": "qty",
+      "type": {
+        "type": "int",
+        "connect.type": "int16"
+      },
+      "default": 1
+    },
+    {
+      "name": "amount",
+      "type": {
+        "type": "bytes",
+        "logicalType": "decimal",
+        "precision": 10,
+        "scale": 2,
+        "connect.name": "org.apache.kafka.connect.data.Decimal",
+        "connect.parameters": {
+          "connect.decimal.precision": "10",
+          "scale": "2"
+        }
+      }
+    },
+    {
+      "name": "status",
+      "type": {
+        "type": "string",
+        "connect.name": "io.debezium.data.Enum",
+        "connect.parameters": {
+          "allowed": "new,paid"
+        }
+      },
+      "default": "new"
+    },
+    {
+      "name": "flags",
+      "type": [
+        "null",
+        {
+          "type": "bytes",
+          "connect.name": "io.debezium.data.Bits",
+          "connect.parameters": {
+            "length": "8"
+          }
+        }
+      ],
+      "default": null
+    },
+    {
+      "name": "paid",
+      "type": "boolean"
+    },
+    {
+      "name": "note",
+      "type": [
+        "null",
+        "string"
+      ],
+      "default": null
+    },
+    {
+      "name": "created_at",
+      "type": {
+        "type": "long",
+        "logicalType": "timestamp-millis",
+        "connect.name": "io.debezium.time.Timestamp"
+      }
+    },
+    {
+      "name": "shipped_at",
+      "type": [
+        "null",
+        {
+          "type": "long",
+          "logicalType": "timestamp-micros",
+          "connect.name": "io.debezium.time.MicroTimestamp"
+        }
+      ],
+      "default": null
+    },
+    {
+      "name": "updated_at",
+      "type": {
+        "type": "string",
+        "connect.name": "io.debezium.time.ZonedTimestamp"
+      }
+    },
+    {
+      "name": "due_date",
+      "type": [
+        "null",
+        {
+          "type": "int",
+          "logicalType": "date",
+          "connect.name": "io.debezium.time.Date"
+        }
+      ],
+      "default": null
+    }
+  ],
+  "connect.name": "shop.app.orders.Value"
+}
+`)
+
+		Convey("Geometry", func() {
+			tables, err := From("CREATE TABLE place (a point NOT NULL, b polygon);")
+			So(err, ShouldBeNil)
+			res, err := GenerateAvro(tables[0], nil)
+			So(err, ShouldBeNil)
+			So(string(res), ShouldContainSubstring, `"name": "Geometry"`)
+			So(string(res), ShouldContainSubstring, "[\n        \"null\",\n        \"io.debezium.data.geometry.Geometry\"\n      ]")
+		})
+	})
+}
@@ -0,0 +1,968 @@
+package sqlparser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Catalog is the schema state built by applying the DDL statements in order.
+// The zero value is an empty catalog ready to use
+type Catalog struct {
+	Schemas []*Schema `json:"schemas,omitempty" yaml:"schemas,omitempty"`
+}
+
+// Schema is a database of the catalog, the tables not qualified
+// and without USE statement are kept by the schema named ""
+type Schema struct {
+	Database *Database `json:"database,omitempty" yaml:"database,omitempty"`
+	Tables   []*Table  `json:"tables,omitempty" yaml:"tables,omitempty"`
+	Views    []*View   `json:"views,omitempty" yaml:"views,omitempty"`
+
+	// Implicit is true if the schema is referred by a table rather than created
+	Implicit bool `json:"implicit,omitempty" yaml:"implicit,omitempty"`
+}
+
+// CatalogError is the semantic error of a statement, e.g. altering a missing table
+type CatalogError struct {
+	File    string
+	Span    Span // zero if the statement is applied without location
+	Message string
+}
+
+func (e *CatalogError) Error() string {
+	if e.Span.Line == 0 {
+		return e.Message
+	}
+	str := fmt.Sprintf("line %d:%d %s", e.Span.Line, e.Span.Column, e.Message)
+	if e.File != "" {
+		return e.File + " " + str
+	}
+	return str
+}
+
+// Apply replay the statement, a *Statement or the statement returned by Statements.
+// The statements other than tables, indexes, views and databases are ignored.
+// If an error returned, the catalog is not changed
+func (c *Catalog) Apply(stmt interface{}) error {
+	var file string
+	var span Span
+	if s, ok := stmt.(*Statement); ok {
+		file, span = s.File, s.Span
+		stmt = s.Node
+	}
+	if err := c.apply(stmt); err != "" {
+		return &CatalogError{File: file, Span: span, Message: err}
+	}
+	return nil
+}
+
+// Schema return the schema by name, nil if not exists.
+// The names of the schemas, tables and views are case insensitive like the columns
+func (c *Catalog) Schema(name string) *Schema {
+	for _, s := range c.Schemas {
+		if strings.EqualFold(s.Database.Name, name) {
+			return s
+		}
+	}
+	return nil
+}
+
+// Table return the table by the schema and name, nil if not exists
+func (c *Catalog) Table(schema, name string) *Table {
+	if s := c.Schema(schema); s != nil {
+		return s.table(name)
+	}
+	return nil
+}
+
+// View return the view by the schema and name, nil if not exists
+func (c *Catalog) View(schema, name string) *View {
+	if s := c.Schema(schema); s != nil {
+		return s.view(name)
+	}
+	return nil
+}
+
+// Tables return the tables of every schema
+func (c *Catalog) Tables() []*Table {
+	var res []*Table
+	for _, s := range c.Schemas {
+		res = append(res, s.Tables...)
+	}
+	return res
+}
+
+func (s *Schema) table(name string) *Table {
+	for _, t := range s.Tables {
+		if strings.EqualFold(t.Name, name) {
+			return t
+		}
+	}
+	return nil
+}
+
+func (s *Schema) view(name string) *View {
+	for _, v := range s.Views {
+		if strings.EqualFold(v.Name.Name, name) {
+			return v
+		}
+	}
+	return nil
+}
+
+// schema return the schema by name, created implicitly if not exists
+func (c *Catalog) schema(name string) *Schema {
+	if s := c.Schema(name); s != nil {
+		return s
+	}
+	s := &Schema{Database: &Database{Name: name}, Implicit: true}
+	c.Schemas = append(c.Schemas, s)
+	return s
+}
+
+// apply return the error message, empty if succeeded
+func (c *Catalog) apply(stmt interface{}) string {
+	switch s := stmt.(type) {
+	case *CreateDatabase:
+		return c.createDatabase(s)
+	case *AlterDatabase:
+		return c.alterDatabase(s)
+	case *DropDatabase:
+		return c.dropDatabase(s)
+	case *CreateTable:
+		return c.createTable(s)
+	case *AlterTable:
+		return c.alterTable(s)
+	case *DropTable:
+		return c.dropTable(s)
+	case *RenameTable:
+		return c.renameTable(s)
+	case *TruncateTable:
+		if c.Table(s.Table.Schema, s.Table.Name) == nil {
+			return fmt.Sprintf("table '%s' doesn't exist", s.Table)
+		}
+	case *CreateIndex:
+		return c.alterTable(&AlterTable{
+			Table: s.Table,
+			Specs: []*AlterSpecification{{Action: AlterAddConstraint, Constraint: s.Constraint}},
+		})
+	case *DropIndex:
+		return c.alterTable(&AlterTable{
+			Table: s.Table,
+			Specs: []*AlterSpecification{{Action: AlterDropIndex, Name: s.Name}},
+		})
+	case *CreateView:
+		return c.createView(s)
+	case *AlterView:
+		return c.alterView(s)
+	case *DropView:
+		return c.dropView(s)
+	}
+	return ""
+}
+
+// --- database start
+
+func (c *Catalog) createDatabase(stmt *CreateDatabase) string {
+	db := *stmt.Database
+	if s := c.Schema(db.Name); s != nil {
+		if s.Implicit {
+			s.Database = &db
+			s.Implicit = false
+			return ""
+		}
+		if stmt.IfNotExists {
+			return ""
+		}
+		return fmt.Sprintf("can't create database '%s'; database exists", db.Name)
+	}
+	c.Schemas = append(c.Schemas, &Schema{Database: &db})
+	return ""
+}
+
+func (c *Catalog) alterDatabase(stmt *AlterDatabase) string {
+	s := c.Schema(stmt.Database.Name)
+	if s == nil {
+		return fmt.Sprintf("unknown database '%s'", stmt.Database.Name)
+	}
+	db := *s.Database
+	if stmt.Database.Charset != "" {
+		db.Charset = stmt.Database.Charset
+	}
+	if stmt.Database.Collation != "" {
+		db.Collation = stmt.Database.Collation
+	}
+	if stmt.Database.Encryption != "" {
+		db.Encryption = stmt.Database.Encryption
+	}
+	if stmt.Database.ReadOnly != "" {
+		db.ReadOnly = stmt.Database.ReadOnly
+	}
+	s.Database = &db
+	return ""
+}
+
+func (c *Catalog) dropDatabase(stmt *DropDatabase) string {
+	for i, s := range c.Schemas {
+		if strings.EqualFold(s.Database.Name, stmt.Name) {
+			c.Schemas = append(c.Schemas[:i:i], c.Schemas[i+1:]...)
+			return ""
+		}
+	}
+	if stmt.IfExists {
+		return ""
+	}
+	return fmt.Sprintf("can't drop database '%s'; database doesn't exist", stmt.Name)
+}
+
+// --- database end
+
+// --- table start
+
+func (c *Catalog) createTable(stmt *CreateTable) string {
+	name := &TableName{Schema: stmt.Schema, Name: onlyTableName(stmt.Name)}
+	if c.Table(name.Schema, name.Name) != nil || c.View(name.Schema, name.Name) != nil {
+		if stmt.IfNotExists {
+			return ""
+		}
+		return fmt.Sprintf("table '%s' already exists", name)
+	}
+
+	t := cloneTable(stmt.Convert())
+	t.Constraints = nil
+	for _, col := range t.Columns {
+		if t.Column(col.Name) != col {
+			return fmt.Sprintf("duplicate column name '%s'", col.Name)
+		}
+	}
+	for _, col := range t.Columns {
+		if err := addColumnKeys(t, col); err != "" {
+			return err
+		}
+	}
+	// the foreign keys are added at last, so the indexes defined after them can be used
+	for _, fk := range []bool{false, true} {
+		for _, cons := range stmt.Constraints {
+			if isForeignKey(cons) != fk {
+				continue
+			}
+			if err := addConstraint(t, cloneConstraint(cons)); err != "" {
+				return err
+			}
+		}
+	}
+	s := c.schema(name.Schema)
+	s.Tables = append(s.Tables, t)
+	return ""
+}
+
+func (c *Catalog) alterTable(stmt *AlterTable) string {
+	s := c.Schema(stmt.Table.Schema)
+	var origin *Table
+	if s != nil {
+		origin = s.table(stmt.Table.Name)
+	}
+	if origin == nil {
+		return fmt.Sprintf("table '%s' doesn't exist", stmt.Table)
+	}
+
+	// the specifications are applied to a copy, so the table is not changed on error
+	t := cloneTable(origin)
+	var renameTo *TableName
+	for _, spec := range stmt.Specs {
+		if spec.Action == AlterRenameTable {
+			renameTo = spec.RenameTo
+			continue
+		}
+		if err := alterSpecification(t, spec); err != "" {
+			return err
+		}
+	}
+
+	if renameTo != nil && (renameTo.Schema != t.Schema || renameTo.Name != t.Name) {
+		to := c.schema(renameTo.Schema)
+		if exists := to.table(renameTo.Name); exists != nil && exists != origin || to.view(renameTo.Name) != nil {
+			return fmt.Sprintf("table '%s' already exists", renameTo)
+		}
+		s.Tables = removeTable(s.Tables, origin)
+		t.Schema, t.Name = renameTo.Schema, renameTo.Name
+		to.Tables = append(to.Tables, t)
+		return ""
+	}
+	for i := range s.Tables {
+		if s.Tables[i] == origin {
+			s.Tables[i] = t
+		}
+	}
+	return ""
+}
+
+func (c *Catalog) dropTable(stmt *DropTable) string {
+	var missing []string
+	for _, name := range stmt.Tables {
+		if c.Table(name.Schema, name.Name) == nil {
+			missing = append(missing, name.String())
+		}
+	}
+	if len(missing) > 0 && !stmt.IfExists {
+		return fmt.Sprintf("unknown table '%s'", strings.Join(missing, ","))
+	}
+	for _, name := range stmt.Tables {
+		if t := c.Table(name.Schema, name.Name); t != nil {
+			s := c.Schema(name.Schema)
+			s.Tables = removeTable(s.Tables, t)
+		}
+	}
+	return ""
+}
+
+// renameTable rename the tables in order, the catalog is restored if any fails
+func (c *Catalog) renameTable(stmt *RenameTable) string {
+	backup := make([]Schema, len(c.Schemas))
+	for i, s := range c.Schemas {
+		backup[i] = *s
+	}
+	restore := func() {
+		for i, s := range c.Schemas[:len(backup)] {
+			*s = backup[i]
+		}
+		c.Schemas = c.Schemas[:len(backup)]
+	}
+
+	for _, clause := range stmt.Clauses {
+		t := c.Table(clause.From.Schema, clause.From.Name)
+		if t == nil {
+			restore()
+			return fmt.Sprintf("table '%s' doesn't exist", clause.From)
+		}
+		to := c.schema(clause.To.Schema)
+		if exists := to.table(clause.To.Name); exists != nil && exists != t || to.view(clause.To.Name) != nil {
+			restore()
+			return fmt.Sprintf("table '%s' already exists", clause.To)
+		}
+		from := c.Schema(clause.From.Schema)
+		from.Tables = removeTable(from.Tables, t)
+		renamed := *t
+		renamed.Schema, renamed.Name = clause.To.Schema, clause.To.Name
+		to.Tables = append(to.Tables, &renamed)
+	}
+	return ""
+}
+
+func removeTable(tables []*Table, t *Table) []*Table {
+	var res []*Table
+	for _, tbl := range tables {
+		if tbl != t {
+			res = append(res, tbl)
+		}
+	}
+	return res
+}
+
+// alterSpecification apply one specification to the table
+func alterSpecification(t *Table, spec *AlterSpecification) string {
+	switch spec.Action {
+	case AlterAddColumn:
+		col := cloneColumn(spec.Column)
+		if t.Column(col.Name) != nil {
+			return fmt.Sprintf("duplicate column name '%s'", col.Name)
+		}
+		if err := insertColumn(t, col, spec); err != "" {
+			return err
+		}
+		return addColumnKeys(t, col)
+	case AlterChangeColumn, AlterModifyColumn:
+		old := t.Column(spec.Name)
+		if old == nil {
+			return fmt.Sprintf("unknown column '%s' in '%s'", spec.Name, t.Name)
+		}
+		col := cloneColumn(spec.Column)
+		if other := t.Column(col.Name); other != nil && other != old {
+			return fmt.Sprintf("duplicate column name '%s'", col.Name)
+		}
+		if spec.First || spec.After != "" {
+			t.Columns = removeColumn(t.Columns, old)
+			if err := insertColumn(t, col, spec); err != "" {
+				return err
+			}
+		} else {
+			for i := range t.Columns {
+				if t.Columns[i] == old {
+					t.Columns[i] = col
+				}
+			}
+		}
+		renameKeyColumn(t, old.Name, col.Name)
+		if err := addColumnKeys(t, col); err != "" {
+			return err
+		}
+		if pk := primaryKey(t); pk != nil && containsName(pk.ColumnPrimaryKey, col.Name) {
+			col.Constraint.NotNull = true
+		}
+	case AlterRenameColumn:
+		col := t.Column(spec.Name)
+		if col == nil {
+			return fmt.Sprintf("unknown column '%s' in '%s'", spec.Name, t.Name)
+		}
+		if other := t.Column(spec.NewName); other != nil && other != col {
+			return fmt.Sprintf("duplicate column name '%s'", spec.NewName)
+		}
+		renameKeyColumn(t, col.Name, spec.NewName)
+		col.Name = spec.NewName
+	case AlterDropColumn:
+		col := t.Column(spec.Name)
+		if col == nil {
+			return fmt.Sprintf("can't DROP '%s'; check that column/key exists", spec.Name)
+		}
+		if len(t.Columns) == 1 {
+			return "you can't delete all columns with ALTER TABLE; use DROP TABLE instead"
+		}
+		for _, cons := range t.Constraints {
+			if cons.ColumnForeignKey != nil && containsName(cons.ColumnForeignKey, col.Name) {
+				return fmt.Sprintf("cannot drop column '%s': needed in a foreign key constraint '%s'", col.Name, cons.Name)
+			}
+		}
+		t.Columns = removeColumn(t.Columns, col)
+		dropKeyColumn(t, col.Name)
+	case AlterSetDefault, AlterDropDefault:
+		col := t.Column(spec.Name)
+		if col == nil {
+			return fmt.Sprintf("unknown column '%s' in '%s'", spec.Name, t.Name)
+		}
+		col.Constraint.DefaultValue = nil
+		if spec.Action == AlterSetDefault && spec.Default != nil {
+			def := *spec.Default
+			col.Constraint.DefaultValue = &def
+		}
+	case AlterAddConstraint:
+		return addConstraint(t, cloneConstraint(spec.Constraint))
+	case AlterDropPrimaryKey:
+		pk := primaryKey(t)
+		if pk == nil {
+			return "can't DROP 'PRIMARY'; check that column/key exists"
+		}
+		return dropConstraint(t, pk)
+	case AlterDropIndex:
+		cons := findConstraint(t, spec.Name, isIndex)
+		if cons == nil {
+			return fmt.Sprintf("can't DROP '%s'; check that column/key exists", spec.Name)
+		}
+		return dropConstraint(t, cons)
+	case AlterDropForeignKey:
+		cons := findConstraint(t, spec.Name, isForeignKey)
+		if cons == nil {
+			return fmt.Sprintf("can't DROP '%s'; check that column/key exists", spec.Name)
+		}
+		return dropConstraint(t, cons)
+	case AlterDropCheck:
+		cons := findConstraint(t, spec.Name, isCheck)
+		if cons == nil {
+			return fmt.Sprintf("check constraint '%s' is not found in the table", spec.Name)
+		}
+		return dropConstraint(t, cons)
+	case AlterDropConstraint:
+		cons := findConstraint(t, spec.Name, func(cons *TableConstraint) bool {
+			return cons.ColumnUniqueKey != nil || isForeignKey(cons) || isCheck(cons)
+		})
+		if cons == nil {
+			return fmt.Sprintf("constraint '%s' does not exist", spec.Name)
+		}
+		return dropConstraint(t, cons)
+	case AlterRenameIndex:
+		cons := findConstraint(t, spec.Name, isIndex)
+		if cons == nil || cons.ColumnPrimaryKey != nil {
+			return fmt.Sprintf("key '%s' doesn't exist in table '%s'", spec.Name, t.Name)
+		}
+		if other := findConstraint(t, spec.NewName, isIndex); other != nil && other != cons {
+			return fmt.Sprintf("duplicate key name '%s'", spec.NewName)
+		}
+		cons.Name = spec.NewName
+	case AlterIndexVisibility:
+		if findConstraint(t, spec.Name, isIndex) == nil {
+			return fmt.Sprintf("key '%s' doesn't exist in table '%s'", spec.Name, t.Name)
+		}
+	case AlterTableOptions, AlterConvertCharset:
+		t.Options = mergeTableOptions(t.Options, spec.Options)
+		if spec.Action == AlterConvertCharset {
+			// the character columns are converted to the table charset
+			for _, col := range t.Columns {
+				if col.DataType != nil && (col.DataType.CharsetName != "" || col.DataType.CollationName != "") {
+					dt := *col.DataType
+					dt.CharsetName, dt.CollationName = "", ""
+					for _, clause := range []string{" CHARACTER SET ", " COLLATE "} {
+						if i := strings.Index(dt.Source, clause); i >= 0 {
+							dt.Source = dt.Source[:i]
+						}
+					}
+					col.DataType = &dt
+				}
+			}
+		}
+	case AlterPartitionBy:
+		t.Partition = clonePartitioning(spec.Partition)
+	case AlterRemovePartition, AlterAddPartition, AlterDropPartition:
+		if t.Partition == nil {
+			return "partition management on a not partitioned table is not possible"
+		}
+		if spec.Action == AlterRemovePartition {
+			t.Partition = nil
+			return ""
+		}
+		return alterPartition(t.Partition, spec)
+	}
+	return ""
+}
+
+func alterPartition(part *Partitioning, spec *AlterSpecification) string {
+	switch spec.Action {
+	case AlterAddPartition:
+		for _, p := range spec.Partition.Partitions {
+			for _, old := range part.Partitions {
+				if strings.EqualFold(old.Name, p.Name) {
+					return fmt.Sprintf("duplicate partition name %s", p.Name)
+				}
+			}
+			part.Partitions = append(part.Partitions, p)
+		}
+	case AlterDropPartition:
+		var res []*Partition
+		for _, p := range part.Partitions {
+			if !strings.EqualFold(p.Name, spec.Name) {
+				res = append(res, p)
+			}
+		}
+		if len(res) == len(part.Partitions) {
+			return fmt.Sprintf("error in list of partitions to DROP: %s", spec.Name)
+		}
+		part.Partitions = res
+	}
+	return ""
+}
+
+// insertColumn insert the column at FIRST, AFTER or the end
+func insertColumn(t *Table, col *Column, spec *AlterSpecification) string {
+	switch {
+	case spec.First:
+		t.Columns = append([]*Column{col}, t.Columns...)
+	case spec.After != "":
+		after := t.Column(spec.After)
+		if after == nil {
+			return fmt.Sprintf("unknown column '%s' in '%s'", spec.After, t.Name)
+		}
+		var res []*Column
+		for _, c := range t.Columns {
+			res = append(res, c)
+			if c == after {
+				res = append(res, col)
+			}
+		}
+		t.Columns = res
+	default:
+		t.Columns = append(t.Columns, col)
+	}
+	return ""
+}
+
+func removeColumn(cols []*Column, col *Column) []*Column {
+	var res []*Column
+	for _, c := range cols {
+		if c != col {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+func mergeTableOptions(options, changes *TableOptions) *TableOptions {
+	var res TableOptions
+	if options != nil {
+		res = *options
+	}
+	if changes == nil {
+		return &res
+	}
+	if changes.Engine != "" {
+		res.Engine = changes.Engine
+	}
+	if changes.Charset != "" {
+		res.Charset = changes.Charset
+		// the collation is the default of the new charset unless specified
+		res.Collation = changes.Collation
+	}
+	if changes.Collation != "" {
+		res.Collation = changes.Collation
+	}
+	if changes.Comment != "" {
+		res.Comment = changes.Comment
+	}
+	if changes.AutoIncrement != "" {
+		res.AutoIncrement = changes.AutoIncrement
+	}
+	if changes.RowFormat != "" {
+		res.RowFormat = changes.RowFormat
+	}
+	return &res
+}
+
+// --- table end
+
+// --- constraint start
+
+// addColumnKeys move the PRIMARY KEY and UNIQUE of the column definition to the table constraints
+func addColumnKeys(t *Table, col *Column) string {
+	if col.Constraint == nil {
+		return ""
+	}
+	if col.Constraint.Primary || col.Constraint.Key {
+		col.Constraint.Primary, col.Constraint.Key = false, false
+		if err := addConstraint(t, &TableConstraint{ColumnPrimaryKey: []string{col.Name}}); err != "" {
+			return err
+		}
+	}
+	if col.Constraint.Unique {
+		col.Constraint.Unique = false
+		if err := addConstraint(t, &TableConstraint{ColumnUniqueKey: []string{col.Name}}); err != "" {
+			return err
+		}
+	}
+	return ""
+}
+
+// addConstraint check and add the constraint, the name is generated like MySQL if not named
+func addConstraint(t *Table, cons *TableConstraint) string {
+	for _, name := range constraintColumns(cons) {
+		if strings.HasPrefix(name, "(") {
+			// functional key part
+			continue
+		}
+		if t.Column(name) == nil {
+			return fmt.Sprintf("key column '%s' doesn't exist in table", name)
+		}
+	}
+
+	switch {
+	case cons.ColumnPrimaryKey != nil:
+		if primaryKey(t) != nil {
+			return "multiple primary key defined"
+		}
+		cons.Name = "PRIMARY"
+		for _, name := range cons.ColumnPrimaryKey {
+			if col := t.Column(name); col != nil && col.Constraint != nil {
+				col.Constraint.NotNull = true
+			}
+		}
+	case isForeignKey(cons):
+		if cons.Name == "" {
+			cons.Name = t.Name + "_ibfk_" + strconv.Itoa(nextSuffix(t, t.Name+"_ibfk_"))
+		} else if findConstraint(t, cons.Name, isForeignKey) != nil {
+			return fmt.Sprintf("duplicate foreign key constraint name '%s'", cons.Name)
+		}
+	case isCheck(cons):
+		if cons.Name == "" {
+			cons.Name = t.Name + "_chk_" + strconv.Itoa(nextSuffix(t, t.Name+"_chk_"))
+		} else if findConstraint(t, cons.Name, isCheck) != nil {
+			return fmt.Sprintf("duplicate check constraint name '%s'", cons.Name)
+		}
+	default:
+		if cons.Name == "" {
+			cons.Name = indexName(t, constraintColumns(cons)[0])
+		} else if strings.EqualFold(cons.Name, "PRIMARY") {
+			return fmt.Sprintf("incorrect index name '%s'", cons.Name)
+		} else if findConstraint(t, cons.Name, isIndex) != nil {
+			return fmt.Sprintf("duplicate key name '%s'", cons.Name)
+		}
+	}
+	t.Constraints = append(t.Constraints, cons)
+
+	// the foreign key need an index starting with its columns, created if not exists
+	if isForeignKey(cons) && supportingIndex(t, cons, nil) == nil {
+		name := cons.Name
+		if strings.HasPrefix(name, t.Name+"_ibfk_") || findConstraint(t, name, isIndex) != nil {
+			name = indexName(t, cons.ColumnForeignKey[0])
+		}
+		idx := &TableConstraint{Name: name, ColumnIndex: append([]string(nil), cons.ColumnForeignKey...)}
+		t.Constraints = append(t.Constraints[:len(t.Constraints)-1], idx, cons)
+	}
+	return ""
+}
+
+// dropConstraint remove the constraint, the index needed by foreign key can't be dropped
+func dropConstraint(t *Table, cons *TableConstraint) string {
+	if isIndex(cons) {
+		for _, fk := range t.Constraints {
+			if isForeignKey(fk) && supportingIndex(t, fk, cons) == nil {
+				return fmt.Sprintf("cannot drop index '%s': needed in a foreign key constraint", cons.Name)
+			}
+		}
+	}
+	var res []*TableConstraint
+	for _, c := range t.Constraints {
+		if c != cons {
+			res = append(res, c)
+		}
+	}
+	t.Constraints = res
+	return ""
+}
+
+// supportingIndex return the index whose leading columns are the foreign key columns, except the ignored one
+func supportingIndex(t *Table, fk *TableConstraint, ignored *TableConstraint) *TableConstraint {
+	return leadingIndex(t, fk.ColumnForeignKey, ignored)
+}
+
+// leadingIndex return the index whose leading columns are the columns, except the ignored one
+func leadingIndex(t *Table, columns []string, ignored *TableConstraint) *TableConstraint {
+	for _, cons := range t.Constraints {
+		if cons == ignored || !isIndex(cons) || cons.IndexKind != "" {
+			continue
+		}
+		cols := constraintColumns(cons)
+		if len(cols) < len(columns) {
+			continue
+		}
+		matched := true
+		for i, name := range columns {
+			if !strings.EqualFold(cols[i], name) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return cons
+		}
+	}
+	return nil
+}
+
+// renameKeyColumn rename the column in constraints, like CHANGE COLUMN does
+func renameKeyColumn(t *Table, from, to string) {
+	for _, cons := range t.Constraints {
+		for _, cols := range [][]string{cons.ColumnPrimaryKey, cons.ColumnUniqueKey, cons.ColumnForeignKey, cons.ColumnIndex} {
+			for i := range cols {
+				if strings.EqualFold(cols[i], from) {
+					cols[i] = to
+				}
+			}
+		}
+	}
+}
+
+// dropKeyColumn remove the column from the indexes, the index is dropped if no column left
+func dropKeyColumn(t *Table, name string) {
+	var res []*TableConstraint
+	for _, cons := range t.Constraints {
+		cons.ColumnPrimaryKey = removeName(cons.ColumnPrimaryKey, name)
+		cons.ColumnUniqueKey = removeName(cons.ColumnUniqueKey, name)
+		cons.ColumnIndex = removeName(cons.ColumnIndex, name)
+		if isIndex(cons) || isForeignKey(cons) || isCheck(cons) {
+			res = append(res, cons)
+		}
+	}
+	t.Constraints = res
+}
+
+// removeName keep nil as nil, return nil if the last name removed
+func removeName(names []string, name string) []string {
+	if names == nil {
+		return nil
+	}
+	var res []string
+	for _, n := range names {
+		if !strings.EqualFold(n, name) {
+			res = append(res, n)
+		}
+	}
+	return res
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// indexName return the column name, or with the suffix like _2 if exists
+func indexName(t *Table, column string) string {
+	name := column
+	for i := 2; findConstraint(t, name, isIndex) != nil || strings.EqualFold(name, "PRIMARY"); i++ {
+		name = column + "_" + strconv.Itoa(i)
+	}
+	return name
+}
+
+// nextSuffix return the next number of the generated name like t_ibfk_1
+func nextSuffix(t *Table, prefix string) int {
+	res := 1
+	for _, cons := range t.Constraints {
+		if !strings.HasPrefix(cons.Name, prefix) {
+			continue
+		}
+		if n, err := strconv.Atoi(strings.TrimPrefix(cons.Name, prefix)); err == nil && n >= res {
+			res = n + 1
+		}
+	}
+	return res
+}
+
+func primaryKey(t *Table) *TableConstraint {
+	for _, cons := range t.Constraints {
+		if cons.ColumnPrimaryKey != nil {
+			return cons
+		}
+	}
+	return nil
+}
+
+// findConstraint return the constraint by name (case insensitive) matching the kind
+func findConstraint(t *Table, name string, kind func(*TableConstraint) bool) *TableConstraint {
+	for _, cons := range t.Constraints {
+		if kind(cons) && strings.EqualFold(cons.Name, name) {
+			return cons
+		}
+	}
+	return nil
+}
+
+// isIndex report whether the constraint is primary key, unique key or index
+func isIndex(cons *TableConstraint) bool {
+	return cons.ColumnPrimaryKey != nil || cons.ColumnUniqueKey != nil || cons.ColumnIndex != nil
+}
+
+func isForeignKey(cons *TableConstraint) bool {
+	return cons.ColumnForeignKey != nil
+}
+
+func isCheck(cons *TableConstraint) bool {
+	return cons.Check != ""
+}
+
+func constraintColumns(cons *TableConstraint) []string {
+	switch {
+	case cons.ColumnPrimaryKey != nil:
+		return cons.ColumnPrimaryKey
+	case cons.ColumnUniqueKey != nil:
+		return cons.ColumnUniqueKey
+	case cons.ColumnForeignKey != nil:
+		return cons.ColumnForeignKey
+	default:
+		return cons.ColumnIndex
+	}
+}
+
+// --- constraint end
+
+// --- view start
+
+func (c *Catalog) createView(stmt *CreateView) string {
+	view := *stmt.View
+	if c.Table(view.Name.Schema, view.Name.Name) != nil {
+		return fmt.Sprintf("table '%s' already exists", view.Name)
+	}
+	s := c.schema(view.Name.Schema)
+	if old := s.view(view.Name.Name); old != nil {
+		if !stmt.OrReplace {
+			return fmt.Sprintf("table '%s' already exists", view.Name)
+		}
+		for i := range s.Views {
+			if s.Views[i] == old {
+				s.Views[i] = &view
+			}
+		}
+		return ""
+	}
+	s.Views = append(s.Views, &view)
+	return ""
+}
+
+func (c *Catalog) alterView(stmt *AlterView) string {
+	s := c.Schema(stmt.View.Name.Schema)
+	if s == nil || s.view(stmt.View.Name.Name) == nil {
+		return fmt.Sprintf("table '%s' doesn't exist", stmt.View.Name)
+	}
+	return c.createView(&CreateView{OrReplace: true, View: stmt.View})
+}
+
+func (c *Catalog) dropView(stmt *DropView) string {
+	var missing []string
+	for _, name := range stmt.Views {
+		if c.View(name.Schema, name.Name) == nil {
+			missing = append(missing, name.String())
+		}
+	}
+	if len(missing) > 0 && !stmt.IfExists {
+		return fmt.Sprintf("unknown table '%s'", strings.Join(missing, ","))
+	}
+	for _, name := range stmt.Views {
+		if s := c.Schema(name.Schema); s != nil {
+			var res []*View
+			for _, view := range s.Views {
+				if !strings.EqualFold(view.Name.Name, name.Name) {
+					res = append(res, view)
+				}
+			}
+			s.Views = res
+		}
+	}
+	return ""
+}
+
+// --- view end
+
+// cloneTable copy the table, so the columns and constraints can be changed
+// without affecting the statements and the other states
+func cloneTable(t *Table) *Table {
+	res := *t
+	res.Columns = make([]*Column, len(t.Columns))
+	for i, col := range t.Columns {
+		res.Columns[i] = cloneColumn(col)
+	}
+	res.Constraints = make([]*TableConstraint, len(t.Constraints))
+	for i, cons := range t.Constraints {
+		res.Constraints[i] = cloneConstraint(cons)
+	}
+	if t.Options != nil {
+		options := *t.Options
+		res.Options = &options
+	}
+	res.Partition = clonePartitioning(t.Partition)
+	return &res
+}
+
+func clonePartitioning(part *Partitioning) *Partitioning {
+	if part == nil {
+		return nil
+	}
+	res := *part
+	res.Partitions = append([]*Partition(nil), part.Partitions...)
+	return &res
+}
+
+func cloneColumn(col *Column) *Column {
+	res := *col
+	if col.Constraint != nil {
+		cons := *col.Constraint
+		res.Constraint = &cons
+	} else {
+		res.Constraint = new(ColumnConstraint)
+	}
+	return &res
+}
+
+func cloneConstraint(cons *TableConstraint) *TableConstraint {
+	res := *cons
+	clone := func(names []string) []string {
+		if names == nil {
+			return nil
+		}
+		return append([]string{}, names...)
+	}
+	res.ColumnPrimaryKey = clone(cons.ColumnPrimaryKey)
+	res.ColumnUniqueKey = clone(cons.ColumnUniqueKey)
+	res.ColumnForeignKey = clone(cons.ColumnForeignKey)
+	res.ColumnIndex = clone(cons.ColumnIndex)
+	return &res
+}
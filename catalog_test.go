@@ -0,0 +1,159 @@
+package sqlparser
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// replay apply every statement of str, return the first error
+func replay(c *Catalog, str string) error {
+	stmts, err := Parse(str)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range stmts {
+		if err := c.Apply(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestCatalog(t *testing.T) {
+	Convey("TestCatalog", t, func() {
+		c := new(Catalog)
+
+		Convey("CreateTable", func() {
+			err := replay(c, "CREATE TABLE user (id int PRIMARY KEY, email varchar(64) UNIQUE, org_id int, "+
+				"CONSTRAINT fk_org FOREIGN KEY (org_id) REFERENCES org (id), CHECK (id > 0))")
+			So(err, ShouldBeNil)
+
+			tbl := c.Table("", "user")
+			So(tbl, ShouldNotBeNil)
+			So(tbl.Column("ID").Constraint.NotNull, ShouldBeTrue)
+			So(tbl.Column("id").Constraint.Primary, ShouldBeFalse)
+			So(tbl.Constraints, ShouldResemble, []*TableConstraint{
+				{ColumnPrimaryKey: []string{"id"}, Name: "PRIMARY"},
+				{ColumnUniqueKey: []string{"email"}, Name: "email"},
+				{Check: "id > 0", Name: "user_chk_1"},
+				{ColumnIndex: []string{"org_id"}, Name: "fk_org"},
+				{
+					ColumnForeignKey: []string{"org_id"},
+					Name:             "fk_org",
+					Reference:        &Reference{Table: &TableName{Name: "org"}, Columns: []string{"id"}},
+				},
+			})
+
+			So(replay(c, "CREATE TABLE IF NOT EXISTS user (x int)"), ShouldBeNil)
+			So(len(c.Table("", "user").Columns), ShouldEqual, 3)
+		})
+
+		Convey("AlterTable", func() {
+			err := replay(c, `CREATE TABLE t (a int, b int, c int, KEY idx_bc (b, c));
+ALTER TABLE t ADD COLUMN d int FIRST, CHANGE b b2 bigint AFTER c, DROP COLUMN c, ADD INDEX (a);
+CREATE INDEX idx_d ON t (d);
+ALTER TABLE t RENAME INDEX idx_d TO idx_dd, ENGINE = InnoDB, RENAME TO t2;`)
+			So(err, ShouldBeNil)
+			So(c.Table("", "t"), ShouldBeNil)
+
+			tbl := c.Table("", "t2")
+			var names []string
+			for _, col := range tbl.Columns {
+				names = append(names, col.Name)
+			}
+			So(names, ShouldResemble, []string{"d", "a", "b2"})
+			So(tbl.Column("b2").DataType.Name, ShouldEqual, "BIGINT")
+			So(tbl.Constraints, ShouldResemble, []*TableConstraint{
+				{ColumnIndex: []string{"b2"}, Name: "idx_bc"},
+				{ColumnIndex: []string{"a"}, Name: "a"},
+				{ColumnIndex: []string{"d"}, Name: "idx_dd"},
+			})
+			So(tbl.Options, ShouldResemble, &TableOptions{Engine: "InnoDB"})
+
+			So(replay(c, "DROP INDEX idx_dd ON t2; DROP TABLE t2;"), ShouldBeNil)
+			So(c.Tables(), ShouldBeEmpty)
+		})
+
+		Convey("Databases and views", func() {
+			err := replay(c, `CREATE DATABASE app CHARACTER SET utf8mb4; USE app;
+CREATE TABLE t (id int); CREATE VIEW v AS SELECT id FROM t;
+ALTER DATABASE app COLLATE utf8mb4_bin;
+CREATE OR REPLACE VIEW v AS SELECT 1;
+RENAME TABLE t TO other.t;`)
+			So(err, ShouldBeNil)
+			So(c.Schema("app").Database, ShouldResemble, &Database{Name: "app", Charset: "utf8mb4", Collation: "utf8mb4_bin"})
+			So(c.View("app", "v").Select, ShouldEqual, "SELECT 1")
+			So(c.Table("app", "t"), ShouldBeNil)
+			So(c.Table("other", "t"), ShouldNotBeNil)
+
+			So(replay(c, "DROP VIEW app.v; DROP DATABASE app;"), ShouldBeNil)
+			So(c.Schema("app"), ShouldBeNil)
+		})
+
+		Convey("Errors", func() {
+			So(replay(c, "CREATE TABLE t (id int, name varchar(8), KEY idx (name));"), ShouldBeNil)
+
+			tests := []struct {
+				sql  string
+				want string
+			}{
+				{"\n  ALTER TABLE missing ADD x int", "line 2:2 table 'missing' doesn't exist"},
+				{"ALTER TABLE t ADD COLUMN name int", "line 1:0 duplicate column name 'name'"},
+				{"CREATE TABLE t (x int)", "line 1:0 table 't' already exists"},
+				{"CREATE TABLE u (x int, X int)", "line 1:0 duplicate column name 'X'"},
+				{"ALTER TABLE t DROP COLUMN nope", "line 1:0 can't DROP 'nope'; check that column/key exists"},
+				{"ALTER TABLE t ADD INDEX idx (id)", "line 1:0 duplicate key name 'idx'"},
+				{"ALTER TABLE t ADD PRIMARY KEY (id), ADD PRIMARY KEY (name)", "line 1:0 multiple primary key defined"},
+				{"ALTER TABLE t ADD INDEX (nope)", "line 1:0 key column 'nope' doesn't exist in table"},
+				{"DROP INDEX nope ON t", "line 1:0 can't DROP 'nope'; check that column/key exists"},
+				{"DROP TABLE t, missing", "line 1:0 unknown table 'missing'"},
+				{"RENAME TABLE t TO a, missing TO b", "line 1:0 table 'missing' doesn't exist"},
+				{"DROP DATABASE nope", "line 1:0 can't drop database 'nope'; database doesn't exist"},
+				{"CREATE VIEW t AS SELECT 1", "line 1:0 table 't' already exists"},
+			}
+			for _, tt := range tests {
+				err := replay(c, tt.sql)
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, tt.want)
+			}
+
+			// the failed statements change nothing
+			tbl := c.Table("", "t")
+			So(len(tbl.Columns), ShouldEqual, 2)
+			So(tbl.Constraints, ShouldResemble, []*TableConstraint{{ColumnIndex: []string{"name"}, Name: "idx"}})
+			So(c.Table("", "a"), ShouldBeNil)
+			So(len(c.Schemas), ShouldEqual, 1)
+		})
+
+		Convey("Foreign key index", func() {
+			err := replay(c, "CREATE TABLE c (id int, pid int, FOREIGN KEY (pid) REFERENCES p (id));")
+			So(err, ShouldBeNil)
+			tbl := c.Table("", "c")
+			So(tbl.Constraints[0], ShouldResemble, &TableConstraint{ColumnIndex: []string{"pid"}, Name: "pid"})
+			So(tbl.Constraints[1].Name, ShouldEqual, "c_ibfk_1")
+
+			err = replay(c, "ALTER TABLE c DROP INDEX pid")
+			So(err.Error(), ShouldEqual, "line 1:0 cannot drop index 'pid': needed in a foreign key constraint")
+			So(replay(c, "ALTER TABLE c DROP FOREIGN KEY c_ibfk_1, DROP INDEX pid"), ShouldBeNil)
+			So(tbl.Constraints, ShouldHaveLength, 2)
+			So(c.Table("", "c").Constraints, ShouldBeEmpty)
+		})
+
+		Convey("Case insensitive names", func() {
+			err := replay(c, `CREATE DATABASE App; USE app;
+CREATE TABLE User (id int PRIMARY KEY);
+ALTER TABLE user ADD x int;
+CREATE VIEW V AS SELECT id FROM USER;
+ALTER TABLE USER RENAME TO Users;`)
+			So(err, ShouldBeNil)
+			tbl := c.Table("APP", "users")
+			So(tbl, ShouldNotBeNil)
+			So(tbl.Name, ShouldEqual, "Users")
+			So(tbl.Column("x"), ShouldNotBeNil)
+			So(replay(c, "CREATE TABLE app.USERS (id int)").Error(), ShouldEqual, "line 1:0 table 'app.USERS' already exists")
+			So(replay(c, "DROP VIEW app.v; DROP TABLE app.users; DROP DATABASE APP"), ShouldBeNil)
+			So(c.Schemas, ShouldBeEmpty)
+		})
+	})
+}
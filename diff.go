@@ -0,0 +1,482 @@
+package sqlparser
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// The types of Change
+const (
+	ChangeAdd    = "ADD"
+	ChangeDrop   = "DROP"
+	ChangeModify = "MODIFY"
+)
+
+// The objects of Change
+const (
+	ObjectTable      = "TABLE"
+	ObjectColumn     = "COLUMN"
+	ObjectIndex      = "INDEX" // primary key, unique key and index
+	ObjectForeignKey = "FOREIGN KEY"
+	ObjectCheck      = "CHECK"
+	ObjectOptions    = "OPTIONS"
+	ObjectPartition  = "PARTITION"
+)
+
+// Change is a difference between two schemas, Before is nil if added and After is nil if dropped.
+// By the Object, Before and After are *Table, *Column, *TableConstraint, *TableOptions or *Partitioning
+type Change struct {
+	Type   string
+	Object string
+	Table  *TableName
+	Name   string // the column, index or constraint name, empty for the table, options and partition
+	Before interface{}
+	After  interface{}
+
+	// AfterColumn is the previous column in the new table of the added or modified column,
+	// empty if it is the first column
+	AfterColumn string
+	// Moved is true if the position of the modified column changed
+	Moved bool
+}
+
+// Diff return the changes from a to b, the schemas are matched by name
+func Diff(a, b *Catalog) []*Change {
+	var res []*Change
+	for _, sa := range a.Schemas {
+		if b.Schema(sa.Database.Name) == nil {
+			res = append(res, diffTables(sa, nil, sa.Tables, nil)...)
+		}
+	}
+	for _, sb := range b.Schemas {
+		var tables []*Table
+		sa := a.Schema(sb.Database.Name)
+		if sa != nil {
+			tables = sa.Tables
+		}
+		res = append(res, diffTables(sa, sb, tables, sb.Tables)...)
+	}
+	return res
+}
+
+// DiffTables return the changes from a to b, the tables are matched by name only,
+// so the schemas of different names can be compared, e.g. migrations and a dump.
+// The AUTO_INCREMENT table option is ignored
+func DiffTables(a, b []*Table) []*Change {
+	return diffTables(nil, nil, a, b)
+}
+
+// diffTables return the changes from the tables of schema sa to the tables of schema sb,
+// the schemas resolve the default charsets and may be nil
+func diffTables(sa, sb *Schema, a, b []*Table) []*Change {
+	var res []*Change
+	for _, ta := range a {
+		if findTable(b, ta.Name) == nil {
+			res = append(res, &Change{Type: ChangeDrop, Object: ObjectTable, Table: tableName(ta), Before: ta})
+		}
+	}
+	for _, tb := range b {
+		ta := findTable(a, tb.Name)
+		if ta == nil {
+			res = append(res, &Change{Type: ChangeAdd, Object: ObjectTable, Table: tableName(tb), After: tb})
+			continue
+		}
+		res = append(res, diffTable(sa, sb, ta, tb)...)
+	}
+	return res
+}
+
+func findTable(tables []*Table, name string) *Table {
+	for _, t := range tables {
+		if strings.EqualFold(t.Name, name) {
+			return t
+		}
+	}
+	return nil
+}
+
+func tableName(t *Table) *TableName {
+	return &TableName{Schema: t.Schema, Name: t.Name}
+}
+
+func diffTable(sa, sb *Schema, a, b *Table) []*Change {
+	name := tableName(b)
+	res := diffColumns(name, sa, sb, a, b)
+
+	for _, kind := range []struct {
+		object string
+		is     func(*TableConstraint) bool
+		equal  func(x, y *TableConstraint) bool
+	}{
+		{ObjectIndex, isIndex, indexEqual},
+		{ObjectForeignKey, isForeignKey, foreignKeyEqual},
+		{ObjectCheck, isCheck, checkEqual},
+	} {
+		for _, ca := range a.Constraints {
+			if kind.is(ca) && findConstraint(b, ca.Name, kind.is) == nil {
+				res = append(res, &Change{Type: ChangeDrop, Object: kind.object, Table: name, Name: ca.Name, Before: ca})
+			}
+		}
+		for _, cb := range b.Constraints {
+			if !kind.is(cb) {
+				continue
+			}
+			ca := findConstraint(a, cb.Name, kind.is)
+			if ca == nil {
+				res = append(res, &Change{Type: ChangeAdd, Object: kind.object, Table: name, Name: cb.Name, After: cb})
+			} else if !kind.equal(ca, cb) {
+				res = append(res, &Change{Type: ChangeModify, Object: kind.object, Table: name, Name: cb.Name, Before: ca, After: cb})
+			}
+		}
+	}
+
+	if !optionsEqual(sa, sb, a, b) {
+		res = append(res, &Change{Type: ChangeModify, Object: ObjectOptions, Table: name, Before: a.Options, After: b.Options})
+	}
+
+	switch {
+	case a.Partition == nil && b.Partition != nil:
+		res = append(res, &Change{Type: ChangeAdd, Object: ObjectPartition, Table: name, After: b.Partition})
+	case a.Partition != nil && b.Partition == nil:
+		res = append(res, &Change{Type: ChangeDrop, Object: ObjectPartition, Table: name, Before: a.Partition})
+	case a.Partition != nil && !partitionEqual(a.Partition, b.Partition):
+		res = append(res, &Change{Type: ChangeModify, Object: ObjectPartition, Table: name, Before: a.Partition, After: b.Partition})
+	}
+	return res
+}
+
+// diffColumns report the moved columns which are not in the longest common order of both tables
+func diffColumns(name *TableName, sa, sb *Schema, a, b *Table) []*Change {
+	var res []*Change
+	var commonA, commonB []string
+	for _, col := range a.Columns {
+		if b.Column(col.Name) == nil {
+			res = append(res, &Change{Type: ChangeDrop, Object: ObjectColumn, Table: name, Name: col.Name, Before: col})
+		} else {
+			commonA = append(commonA, strings.ToLower(col.Name))
+		}
+	}
+	for _, col := range b.Columns {
+		if a.Column(col.Name) != nil {
+			commonB = append(commonB, strings.ToLower(col.Name))
+		}
+	}
+	kept := longestCommonSequence(commonA, commonB)
+
+	for i, col := range b.Columns {
+		var after string
+		if i > 0 {
+			after = b.Columns[i-1].Name
+		}
+		old := a.Column(col.Name)
+		if old == nil {
+			res = append(res, &Change{Type: ChangeAdd, Object: ObjectColumn, Table: name, Name: col.Name, After: col, AfterColumn: after})
+			continue
+		}
+		moved := !kept[strings.ToLower(col.Name)]
+		if moved || !columnEqual(columnCharset(sa, a, old), columnCharset(sb, b, col)) {
+			res = append(res, &Change{
+				Type:        ChangeModify,
+				Object:      ObjectColumn,
+				Table:       name,
+				Name:        col.Name,
+				Before:      old,
+				After:       col,
+				AfterColumn: after,
+				Moved:       moved,
+			})
+		}
+	}
+	return res
+}
+
+// longestCommonSequence return the names in the longest common subsequence of a and b
+func longestCommonSequence(a, b []string) map[string]bool {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else if lengths[i+1][j] >= lengths[i][j+1] {
+				lengths[i][j] = lengths[i+1][j]
+			} else {
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+	res := map[string]bool{}
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			res[a[i]] = true
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return res
+}
+
+// columnEqual compare the data type, the resolved charset and collation and the constraint.
+// The equivalent types are the same, e.g. INT(11) and INTEGER, DEFAULT NULL is the same as no default,
+// and DEFAULT 1 is the same as DEFAULT '1' of SHOW CREATE TABLE unless the column is a string
+func columnEqual(a, b typedColumn) bool {
+	if (a.DataType == nil) != (b.DataType == nil) ||
+		a.DataType != nil && !reflect.DeepEqual(typeIdentity(a.DataType), typeIdentity(b.DataType)) {
+		return false
+	}
+	if !charsetEqual(a.charset, a.collation, b.charset, b.collation) {
+		return false
+	}
+	normalize := func(cons *ColumnConstraint) ColumnConstraint {
+		var res ColumnConstraint
+		if cons != nil {
+			res = *cons
+		}
+		if res.DefaultValue != nil && !res.DefaultValue.Is {
+			res.DefaultValue = nil
+		}
+		if def := res.DefaultValue; def != nil {
+			res.DefaultValue = &DefaultValue{Value: def.Value, Is: true, Kind: def.Kind}
+			switch def.Kind {
+			case "":
+				// the snapshots of the older versions
+				res.DefaultValue.Kind = DefaultString
+			case DefaultNumber:
+				if a.DataType == nil || typeKind(foreignKeyType(a.DataType)) != "character" {
+					res.DefaultValue.Kind = DefaultString
+				}
+			case DefaultExpression:
+				res.DefaultValue.Value = normalizeExpression(def.Value)
+			}
+		}
+		res.OnUpdate = normalizeExpression(res.OnUpdate)
+		return res
+	}
+	return reflect.DeepEqual(normalize(a.Constraint), normalize(b.Constraint))
+}
+
+func indexEqual(a, b *TableConstraint) bool {
+	return (a.ColumnPrimaryKey != nil) == (b.ColumnPrimaryKey != nil) &&
+		(a.ColumnUniqueKey != nil) == (b.ColumnUniqueKey != nil) &&
+		a.IndexKind == b.IndexKind &&
+		namesEqual(constraintColumns(a), constraintColumns(b))
+}
+
+// foreignKeyEqual compare the referenced schema only if both specified,
+// the unspecified action is the same as NO ACTION and RESTRICT
+func foreignKeyEqual(a, b *TableConstraint) bool {
+	if !namesEqual(a.ColumnForeignKey, b.ColumnForeignKey) {
+		return false
+	}
+	ra, rb := a.Reference, b.Reference
+	if ra == nil || rb == nil {
+		return ra == rb
+	}
+	if !strings.EqualFold(ra.Table.Name, rb.Table.Name) {
+		return false
+	}
+	if ra.Table.Schema != "" && rb.Table.Schema != "" && !strings.EqualFold(ra.Table.Schema, rb.Table.Schema) {
+		return false
+	}
+	action := func(str string) string {
+		if str == "" || str == "RESTRICT" {
+			return "NO ACTION"
+		}
+		return str
+	}
+	return namesEqual(ra.Columns, rb.Columns) &&
+		action(ra.OnDelete) == action(rb.OnDelete) &&
+		action(ra.OnUpdate) == action(rb.OnUpdate)
+}
+
+func checkEqual(a, b *TableConstraint) bool {
+	return normalizeExpression(a.Check) == normalizeExpression(b.Check)
+}
+
+// optionsEqual compare the options of the tables with the resolved default charset and collation
+func optionsEqual(sa, sb *Schema, a, b *Table) bool {
+	var oa, ob TableOptions
+	if a.Options != nil {
+		oa = *a.Options
+	}
+	if b.Options != nil {
+		ob = *b.Options
+	}
+	charsetA, collationA := tableCharset(sa, a)
+	charsetB, collationB := tableCharset(sb, b)
+	if charsetA == "" || charsetB == "" {
+		// compare the specified ones if the server default is unknown
+		charsetA, collationA, charsetB, collationB = oa.Charset, oa.Collation, ob.Charset, ob.Collation
+	}
+	if !charsetEqual(charsetA, collationA, charsetB, collationB) {
+		return false
+	}
+	oa.Charset, oa.Collation, ob.Charset, ob.Collation = "", "", "", ""
+	oa.AutoIncrement, ob.AutoIncrement = "", ""
+	oa.Engine, ob.Engine = strings.ToUpper(oa.Engine), strings.ToUpper(ob.Engine)
+	return oa == ob
+}
+
+// charsetEqual compare the charsets and the collations, the unspecified collation is the default
+// of the charset. The unknown charset of the server default only equals the unknown charset
+func charsetEqual(charsetA, collationA, charsetB, collationB string) bool {
+	if charsetA == "" || charsetB == "" {
+		return charsetA == charsetB && strings.EqualFold(collationA, collationB)
+	}
+	return normalizeCharset(charsetA) == normalizeCharset(charsetB) &&
+		effectiveCollation(charsetA, collationA) == effectiveCollation(charsetB, collationB)
+}
+
+func partitionEqual(a, b *Partitioning) bool {
+	if a.Type != b.Type || a.Count != b.Count || len(a.Partitions) != len(b.Partitions) ||
+		normalizeExpression(a.Expression) != normalizeExpression(b.Expression) ||
+		!namesEqual(a.Columns, b.Columns) {
+		return false
+	}
+	for i := range a.Partitions {
+		if !strings.EqualFold(a.Partitions[i].Name, b.Partitions[i].Name) ||
+			normalizeExpression(a.Partitions[i].Source) != normalizeExpression(b.Partitions[i].Source) {
+			return false
+		}
+	}
+	return true
+}
+
+// namesEqual compare the names case insensitive
+func namesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !strings.EqualFold(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeExpression remove the whitespace, backquotes and the outer parentheses and lower
+// the case outside the quoted strings, so the expression written by hand is the same as
+// SHOW CREATE TABLE output while the string literals are compared as is
+func normalizeExpression(expr string) string {
+	var str strings.Builder
+	var quote rune
+	escaped := false
+	for _, r := range expr {
+		switch {
+		case quote != 0:
+			switch {
+			case escaped:
+				escaped = false
+			case r == '\\':
+				escaped = true
+			case r == quote:
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '`' || unicode.IsSpace(r):
+			continue
+		default:
+			r = unicode.ToLower(r)
+		}
+		str.WriteRune(r)
+	}
+	expr = str.String()
+	for strings.HasPrefix(expr, "(") && strings.HasSuffix(expr, ")") && enclosed(expr) {
+		expr = expr[1 : len(expr)-1]
+	}
+	return expr
+}
+
+// enclosed report whether the first parenthesis is closed by the last character,
+// the parentheses in the quoted strings are skipped
+func enclosed(expr string) bool {
+	depth := 0
+	var quote rune
+	escaped := false
+	for i, ch := range expr {
+		switch {
+		case quote != 0:
+			switch {
+			case escaped:
+				escaped = false
+			case ch == '\\':
+				escaped = true
+			case ch == quote:
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '(':
+			depth++
+		case ch == ')':
+			depth--
+			if depth == 0 {
+				return i == len(expr)-1
+			}
+		}
+	}
+	return false
+}
+
+// defaultCollations is the default collation of the charsets in MySQL 8.0
+var defaultCollations = map[string]string{
+	"armscii8": "armscii8_general_ci",
+	"ascii":    "ascii_general_ci",
+	"big5":     "big5_chinese_ci",
+	"binary":   "binary",
+	"cp1250":   "cp1250_general_ci",
+	"cp1251":   "cp1251_general_ci",
+	"cp1256":   "cp1256_general_ci",
+	"cp1257":   "cp1257_general_ci",
+	"cp850":    "cp850_general_ci",
+	"cp852":    "cp852_general_ci",
+	"cp866":    "cp866_general_ci",
+	"cp932":    "cp932_japanese_ci",
+	"dec8":     "dec8_swedish_ci",
+	"eucjpms":  "eucjpms_japanese_ci",
+	"euckr":    "euckr_korean_ci",
+	"gb18030":  "gb18030_chinese_ci",
+	"gb2312":   "gb2312_chinese_ci",
+	"gbk":      "gbk_chinese_ci",
+	"geostd8":  "geostd8_general_ci",
+	"greek":    "greek_general_ci",
+	"hebrew":   "hebrew_general_ci",
+	"hp8":      "hp8_english_ci",
+	"keybcs2":  "keybcs2_general_ci",
+	"koi8r":    "koi8r_general_ci",
+	"koi8u":    "koi8u_general_ci",
+	"latin1":   "latin1_swedish_ci",
+	"latin2":   "latin2_general_ci",
+	"latin5":   "latin5_turkish_ci",
+	"latin7":   "latin7_general_ci",
+	"macce":    "macce_general_ci",
+	"macroman": "macroman_general_ci",
+	"sjis":     "sjis_japanese_ci",
+	"swe7":     "swe7_swedish_ci",
+	"tis620":   "tis620_thai_ci",
+	"ucs2":     "ucs2_general_ci",
+	"ujis":     "ujis_japanese_ci",
+	"utf16":    "utf16_general_ci",
+	"utf16le":  "utf16le_general_ci",
+	"utf32":    "utf32_general_ci",
+	"utf8mb3":  "utf8mb3_general_ci",
+	"utf8mb4":  "utf8mb4_0900_ai_ci",
+}
+
+// effectiveCollation return the normalized collation, the default of the charset if not specified
+func effectiveCollation(charset, collation string) string {
+	if collation == "" {
+		return defaultCollations[normalizeCharset(charset)]
+	}
+	return normalizeCollation(collation)
+}
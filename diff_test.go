@@ -0,0 +1,151 @@
+package sqlparser
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDiff(t *testing.T) {
+	Convey("TestDiff", t, func() {
+		a, b := new(Catalog), new(Catalog)
+
+		Convey("Same schema", func() {
+			So(replay(a, "CREATE TABLE t (id int PRIMARY KEY, pid int, CONSTRAINT fk FOREIGN KEY (pid) REFERENCES p (id), CHECK (id > 0)) ENGINE=InnoDB AUTO_INCREMENT=1"), ShouldBeNil)
+			So(replay(b, "CREATE TABLE `t` (\n`id` int NOT NULL, `pid` int DEFAULT NULL, PRIMARY KEY (`id`), KEY `fk` (`pid`),\n"+
+				"CONSTRAINT `t_chk_1` CHECK ((`id` > 0)), CONSTRAINT `fk` FOREIGN KEY (`pid`) REFERENCES `p` (`id`)) ENGINE=InnoDB AUTO_INCREMENT=20"), ShouldBeNil)
+			So(Diff(a, b), ShouldBeEmpty)
+		})
+
+		Convey("SHOW CREATE TABLE", func() {
+			So(replay(a, `CREATE DATABASE app; USE app;
+CREATE TABLE org (
+  id integer unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  name varchar(64) NOT NULL COLLATE utf8mb4_0900_ai_ci,
+  active bool NOT NULL DEFAULT 1,
+  price decimal,
+  code char,
+  bio text CHARACTER SET latin1,
+  created_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+CREATE TABLE user (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  org_id int unsigned,
+  email varchar(255) NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_email (email),
+  CONSTRAINT fk_org FOREIGN KEY (org_id) REFERENCES org (id) ON DELETE CASCADE
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;`), ShouldBeNil)
+			// mysqldump of MySQL 8.0
+			So(replay(b, "CREATE DATABASE `app` /*!40100 DEFAULT CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci */; USE `app`;\n"+
+				"CREATE TABLE `org` (\n"+
+				"  `id` int unsigned NOT NULL AUTO_INCREMENT,\n"+
+				"  `name` varchar(64) NOT NULL,\n"+
+				"  `active` tinyint(1) NOT NULL DEFAULT '1',\n"+
+				"  `price` decimal(10,0) DEFAULT NULL,\n"+
+				"  `code` char(1) DEFAULT NULL,\n"+
+				"  `bio` text CHARACTER SET latin1 COLLATE latin1_swedish_ci,\n"+
+				"  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,\n"+
+				"  PRIMARY KEY (`id`)\n"+
+				") ENGINE=InnoDB AUTO_INCREMENT=3 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;\n"+
+				"CREATE TABLE `user` (\n"+
+				"  `id` int NOT NULL AUTO_INCREMENT,\n"+
+				"  `org_id` int unsigned DEFAULT NULL,\n"+
+				"  `email` varchar(255) COLLATE utf8mb4_0900_ai_ci NOT NULL,\n"+
+				"  PRIMARY KEY (`id`),\n"+
+				"  UNIQUE KEY `uk_email` (`email`),\n"+
+				"  KEY `fk_org` (`org_id`),\n"+
+				"  CONSTRAINT `fk_org` FOREIGN KEY (`org_id`) REFERENCES `org` (`id`) ON DELETE CASCADE\n"+
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;"), ShouldBeNil)
+			res := Diff(a, b)
+			var changes [][]string
+			for _, ch := range res {
+				changes = append(changes, []string{ch.Type, ch.Object, ch.Table.Name, ch.Name})
+			}
+			// ENGINE=InnoDB is not specified for the user table
+			So(changes, ShouldResemble, [][]string{{ChangeModify, ObjectOptions, "user", ""}})
+
+			// the different types and collations are still changes
+			So(replay(b, "ALTER TABLE app.org MODIFY code varchar(1), MODIFY name varchar(64) NOT NULL COLLATE utf8mb4_bin, MODIFY price decimal(10,2)"), ShouldBeNil)
+			So(len(Diff(a, b)), ShouldEqual, 4)
+		})
+
+		Convey("Tables", func() {
+			So(replay(a, "CREATE TABLE old (id int); CREATE TABLE same (id int);"), ShouldBeNil)
+			So(replay(b, "CREATE TABLE same (id int); CREATE TABLE new (id int);"), ShouldBeNil)
+
+			res := Diff(a, b)
+			So(len(res), ShouldEqual, 2)
+			So(res[0], ShouldResemble, &Change{Type: ChangeDrop, Object: ObjectTable, Table: &TableName{Name: "old"}, Before: a.Table("", "old")})
+			So(res[1], ShouldResemble, &Change{Type: ChangeAdd, Object: ObjectTable, Table: &TableName{Name: "new"}, After: b.Table("", "new")})
+		})
+
+		Convey("Columns", func() {
+			So(replay(a, "CREATE TABLE t (a int, b int, c int, d int, e int)"), ShouldBeNil)
+			So(replay(b, "CREATE TABLE t (a int, c int, x int, b int, e bigint)"), ShouldBeNil)
+
+			var summary [][]interface{}
+			for _, ch := range Diff(a, b) {
+				summary = append(summary, []interface{}{ch.Type, ch.Object, ch.Name, ch.AfterColumn, ch.Moved})
+			}
+			So(summary, ShouldResemble, [][]interface{}{
+				{ChangeDrop, ObjectColumn, "d", "", false},
+				{ChangeAdd, ObjectColumn, "x", "c", false},
+				{ChangeModify, ObjectColumn, "b", "x", true},
+				{ChangeModify, ObjectColumn, "e", "b", false},
+			})
+		})
+
+		Convey("Constraints, options and partitions", func() {
+			So(replay(a, "CREATE TABLE t (id int, pid int, KEY idx (id), UNIQUE KEY uk (pid), "+
+				"CONSTRAINT fk FOREIGN KEY (pid) REFERENCES p (id), CONSTRAINT chk CHECK (id > 0)) ENGINE=InnoDB "+
+				"PARTITION BY HASH (id) PARTITIONS 4"), ShouldBeNil)
+			So(replay(b, "CREATE TABLE t (id int, pid int, KEY idx (id, pid), KEY idx2 (pid), "+
+				"CONSTRAINT fk FOREIGN KEY (pid) REFERENCES p (id) ON DELETE CASCADE, CONSTRAINT chk CHECK (id > 0)) ENGINE=MyISAM"), ShouldBeNil)
+
+			var summary [][]string
+			for _, ch := range Diff(a, b) {
+				summary = append(summary, []string{ch.Type, ch.Object, ch.Name})
+			}
+			So(summary, ShouldResemble, [][]string{
+				{ChangeDrop, ObjectIndex, "uk"},
+				{ChangeModify, ObjectIndex, "idx"},
+				{ChangeAdd, ObjectIndex, "idx2"},
+				{ChangeModify, ObjectForeignKey, "fk"},
+				{ChangeModify, ObjectOptions, ""},
+				{ChangeDrop, ObjectPartition, ""},
+			})
+		})
+
+		Convey("DiffTables", func() {
+			So(replay(a, "CREATE TABLE t (id int)"), ShouldBeNil)
+			So(replay(b, "USE app; CREATE TABLE t (id int)"), ShouldBeNil)
+			So(len(Diff(a, b)), ShouldEqual, 2)
+			So(DiffTables(a.Schema("").Tables, b.Schema("app").Tables), ShouldBeEmpty)
+		})
+
+		Convey("Case insensitive names", func() {
+			So(replay(a, "CREATE TABLE p (id int PRIMARY KEY); CREATE TABLE User (id int, pid int, CONSTRAINT fk FOREIGN KEY (pid) REFERENCES p (id))"), ShouldBeNil)
+			So(replay(b, "CREATE TABLE P (id int PRIMARY KEY); CREATE TABLE user (id int, pid int, CONSTRAINT fk FOREIGN KEY (pid) REFERENCES P (id))"), ShouldBeNil)
+			So(Diff(a, b), ShouldBeEmpty)
+		})
+
+		Convey("Expressions", func() {
+			So(replay(a, "CREATE TABLE t (a varchar(8) DEFAULT ('a b'), b varchar(8) DEFAULT ('A'), c varchar(8) DEFAULT (concat('x', 'y')), "+
+				"CONSTRAINT chk CHECK (a <> 'X Y'))"), ShouldBeNil)
+			So(replay(b, "CREATE TABLE t (a varchar(8) DEFAULT ('ab'), b varchar(8) DEFAULT ('a'), c varchar(8) DEFAULT (CONCAT( 'x' , 'y' )), "+
+				"CONSTRAINT chk CHECK ((`a` <> 'x y')))"), ShouldBeNil)
+			var summary [][]string
+			for _, ch := range Diff(a, b) {
+				summary = append(summary, []string{ch.Type, ch.Object, ch.Name})
+			}
+			// the quoted strings are compared as is, the rest ignores the case and the whitespace
+			So(summary, ShouldResemble, [][]string{
+				{ChangeModify, ObjectColumn, "a"},
+				{ChangeModify, ObjectColumn, "b"},
+				{ChangeModify, ObjectCheck, "chk"},
+			})
+			So(normalizeExpression("(CONCAT( 'A\\' B' , \"C D\"))"), ShouldEqual, "concat('A\\' B',\"C D\")")
+		})
+	})
+}
@@ -1,6 +1,7 @@
 package sqlparser
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 
@@ -39,3 +40,41 @@ func (el *ErrorListener) ReportAttemptingFullContext(recognizer antlr.Parser, df
 func (el *ErrorListener) ReportContextSensitivity(recognizer antlr.Parser, dfa *antlr.DFA, startIndex, stopIndex, prediction int, configs *antlr.ATNConfigSet) {
 	// TODO:
 }
+
+// SyntaxError is a syntax error of the sql, the line starts at 1 and the column at 0 like ANTLR
+type SyntaxError struct {
+	File   string // the file name, empty if parsed from string
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	str := fmt.Sprintf("line %d:%d %s", e.Line, e.Column, e.Msg)
+	if e.File != "" {
+		return e.File + " " + str
+	}
+	return str
+}
+
+// SyntaxErrors is the syntax errors of the sql in source order
+type SyntaxErrors []*SyntaxError
+
+func (errs SyntaxErrors) Error() string {
+	var str []string
+	for _, err := range errs {
+		str = append(str, err.Error())
+	}
+	return strings.Join(str, "\n")
+}
+
+// syntaxErrorListener collect the syntax errors of the lexer and parser instead of printing them
+type syntaxErrorListener struct {
+	*antlr.DefaultErrorListener
+	file string
+	errs SyntaxErrors
+}
+
+func (el *syntaxErrorListener) SyntaxError(recognizer antlr.Recognizer, offendingSymbol interface{}, line, column int, msg string, e antlr.RecognitionException) {
+	el.errs = append(el.errs, &SyntaxError{File: el.file, Line: line, Column: column, Msg: msg})
+}
@@ -1,36 +1,94 @@
 package sqlparser
 
 import (
+	"os"
+
 	"github.com/antlr4-go/antlr/v4"
 )
 
+// From return the tables of CREATE TABLE in the file or sql string. Unlike Parse it is tolerant,
+// the tables recovered from the syntax errors are returned without error
 func From(name string) ([]*Table, error) {
-	var input antlr.CharStream
-	var err error
-	if FileExists(name) {
-		input, err = antlr.NewFileStream(name)
-	} else {
-		input = antlr.NewInputStream(name)
+	var res []*Table
+
+	str, file, err := readSQL(name)
+	if err != nil {
+		return nil, err
 	}
+	stmts, _ := parse(str, file)
+	for _, stmt := range stmts {
+		if ct, ok := stmt.Node.(*CreateTable); ok {
+			res = append(res, ct.Convert())
+		}
+	}
+	return res, nil
+}
+
+// Statements parse the file or sql string and return every supported statement
+// in source order, e.g. *CreateTable, *DropTable, *RenameTable, *TruncateTable.
+// The executable comments like /*!40101 SET NAMES utf8 */ are parsed as statements
+func Statements(name string) ([]interface{}, error) {
+	stmts, err := Parse(name)
 	if err != nil {
 		return nil, err
 	}
+	var res []interface{}
+	for _, stmt := range stmts {
+		res = append(res, stmt.Node)
+	}
+	return res, nil
+}
+
+// Parse is like Statements but keep the location of every statement.
+// The error is SyntaxErrors if the sql is invalid, no statement is returned then
+func Parse(name string) ([]*Statement, error) {
+	str, file, err := readSQL(name)
+	if err != nil {
+		return nil, err
+	}
+	stmts, err := parse(str, file)
+	if err != nil {
+		return nil, err
+	}
+	return stmts, nil
+}
+
+// readSQL return the content and the file name if name is a file, or name itself
+func readSQL(name string) (string, string, error) {
+	if !FileExists(name) {
+		return name, "", nil
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return "", "", err
+	}
+	return string(data), name, nil
+}
+
+// parse the sql string, file is the name of the file str read from. The statements recovered
+// from the syntax errors are returned with the SyntaxErrors
+func parse(str, file string) ([]*Statement, error) {
+	el := &syntaxErrorListener{DefaultErrorListener: antlr.NewDefaultErrorListener(), file: file}
+	input := antlr.NewInputStream(WithExecutableComments(str, ""))
 	lexer := NewMySqlLexer(input)
+	lexer.RemoveErrorListeners()
+	lexer.AddErrorListener(el)
 	tokens := antlr.NewCommonTokenStream(lexer, antlr.LexerDefaultTokenChannel)
 	p := NewMySqlParser(tokens)
+	p.RemoveErrorListeners()
+	p.AddErrorListener(el)
 
 	v := new(Visitor)
-	var res []*Table
 
-	cts := p.Root().Accept(v)
-	if cts == nil {
-		return res, nil
+	var res []*Statement
+	if stmts, ok := p.Root().SqlStatements().(*SqlStatementsContext); ok {
+		res = v.visitStatements(stmts)
 	}
-
-	if tmp, ok := cts.([]*CreateTable); ok {
-		for _, ct := range tmp {
-			res = append(res, ct.Convert())
-		}
+	for _, stmt := range res {
+		stmt.File = file
+	}
+	if len(el.errs) != 0 {
+		return res, el.errs
 	}
 	return res, nil
 }
@@ -5,6 +5,7 @@ go 1.21.0
 require (
 	github.com/antlr4-go/antlr/v4 v4.13.0
 	github.com/smartystreets/goconvey v1.8.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
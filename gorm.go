@@ -0,0 +1,176 @@
+package sqlparser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GenerateGORM generate the GORM models of the tables like GenerateGo, with the gorm tags of
+// column, type, primaryKey, autoIncrement, index, uniqueIndex, not null, default and comment.
+// A foreign key referencing a table in tables is a belongs to association, and every model
+// has the TableName method
+func GenerateGORM(tables []*Table, opts *GoOptions) ([]byte, error) {
+	g := newGoGenerator(opts)
+	graph := NewDependencyGraph(tables)
+	g.tags = func(t *Table, col *Column) []string {
+		return []string{fmt.Sprintf(`gorm:"%s"`, gormTag(gormColumnSettings(t, col)))}
+	}
+	g.fields = func(t *Table, str *strings.Builder) {
+		used := map[string]bool{}
+		for _, col := range t.Columns {
+			used[pascalCase(col.Name, true)] = true
+		}
+		for _, dep := range graph.Dependencies {
+			if dep.Table == t {
+				writeAssociation(str, dep, used)
+			}
+		}
+	}
+	g.methods = func(t *Table, str *strings.Builder) {
+		name := pascalCase(t.Name, true)
+		str.WriteString(fmt.Sprintf("// TableName return the table name of %s\n", name))
+		str.WriteString(fmt.Sprintf("func (%s) TableName() string {\n\treturn %q\n}\n\n", name, t.Name))
+	}
+
+	var body strings.Builder
+	for _, t := range tables {
+		if err := g.writeStruct(&body, t); err != nil {
+			return nil, err
+		}
+	}
+	return g.source(body.String())
+}
+
+// gormColumnSettings return the gorm tag settings of the column, not escaped
+func gormColumnSettings(t *Table, col *Column) []string {
+	res := []string{"column:" + col.Name}
+	if col.DataType != nil {
+		res = append(res, "type:"+lowerKeywords(dataTypeSQL(col.DataType)))
+	}
+	cons := col.Constraint
+	if cons == nil {
+		cons = &ColumnConstraint{}
+	}
+	if pk := primaryKey(t); cons.Primary || cons.Key || pk != nil && containsName(pk.ColumnPrimaryKey, col.Name) {
+		res = append(res, "primaryKey")
+	}
+	if cons.AutoIncrement {
+		res = append(res, "autoIncrement")
+	}
+	if cons.Unique {
+		res = append(res, "unique")
+	}
+	for _, index := range t.Constraints {
+		kind, columns := "index", index.ColumnIndex
+		if index.ColumnUniqueKey != nil {
+			kind, columns = "uniqueIndex", index.ColumnUniqueKey
+		}
+		for i, name := range columns {
+			if !strings.EqualFold(name, col.Name) {
+				continue
+			}
+			setting := kind + ":" + index.Name
+			if index.Name == "" {
+				setting = kind + ":" + indexName(t, columns[0])
+			}
+			if index.IndexKind != "" {
+				setting += ",class:" + index.IndexKind
+			}
+			if len(columns) > 1 {
+				setting += ",priority:" + strconv.Itoa(i+1)
+			}
+			res = append(res, setting)
+		}
+	}
+	if cons.NotNull {
+		res = append(res, "not null")
+	}
+	if cons.DefaultValue != nil && cons.DefaultValue.Is {
+		res = append(res, "default:"+defaultValueSQL(cons.DefaultValue))
+	}
+	if cons.Comment != "" {
+		res = append(res, "comment:"+cons.Comment)
+	}
+	return res
+}
+
+// writeAssociation write the belongs to field of the foreign key, named by the column
+// without the _id suffix, or by the referenced table if taken
+func writeAssociation(str *strings.Builder, dep *Dependency, used map[string]bool) {
+	columns := dep.Constraint.ColumnForeignKey
+	base := dep.Referenced.Name
+	if lower := strings.ToLower(columns[0]); len(columns) == 1 && strings.HasSuffix(lower, "_id") && len(lower) > 3 {
+		base = columns[0][:len(columns[0])-3]
+	}
+	if used[pascalCase(base, true)] {
+		base = dep.Referenced.Name
+	}
+	if used[pascalCase(base, true)] {
+		base = dep.Referenced.Name + "_" + strings.Join(columns, "_")
+	}
+	field := pascalCase(base, true)
+	used[field] = true
+
+	var foreignKeys, references []string
+	for _, name := range columns {
+		foreignKeys = append(foreignKeys, pascalCase(name, true))
+	}
+	refColumns := dep.Constraint.Reference.Columns
+	if len(refColumns) == 0 {
+		if pk := primaryKey(dep.Referenced); pk != nil {
+			refColumns = pk.ColumnPrimaryKey
+		}
+	}
+	for _, name := range refColumns {
+		references = append(references, pascalCase(name, true))
+	}
+
+	settings := []string{"foreignKey:" + strings.Join(foreignKeys, ",")}
+	if len(references) != 0 {
+		settings = append(settings, "references:"+strings.Join(references, ","))
+	}
+	var actions []string
+	if ref := dep.Constraint.Reference; ref.OnUpdate != "" {
+		actions = append(actions, "OnUpdate:"+ref.OnUpdate)
+	}
+	if ref := dep.Constraint.Reference; ref.OnDelete != "" {
+		actions = append(actions, "OnDelete:"+ref.OnDelete)
+	}
+	if len(actions) != 0 {
+		settings = append(settings, "constraint:"+strings.Join(actions, ","))
+	}
+	str.WriteString(fmt.Sprintf("\t%s *%s `db:\"-\" json:\"%s,omitempty\" gorm:\"%s\"`\n",
+		field, pascalCase(dep.Referenced.Name, true), base, gormTag(settings)))
+}
+
+// gormTag join the settings with the semicolons escaped for gorm, and quoted for the struct tag.
+// The backquote can not be in the raw string of the tag, it is replaced by the single quote
+func gormTag(settings []string) string {
+	escaped := make([]string, len(settings))
+	for i, setting := range settings {
+		escaped[i] = strings.ReplaceAll(setting, ";", `\;`)
+	}
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "`", "'").Replace(strings.Join(escaped, ";"))
+}
+
+// lowerKeywords return the source in lower case except the quoted strings, e.g. the ENUM values
+func lowerKeywords(source string) string {
+	var str strings.Builder
+	var quote rune
+	for _, r := range source {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		default:
+			r = unicode.ToLower(r)
+		}
+		str.WriteRune(r)
+	}
+	return str.String()
+}
@@ -0,0 +1,69 @@
+package sqlparser
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGenerateGORM(t *testing.T) {
+	Convey("TestGenerateGORM", t, func() {
+		tables, err := From(`CREATE TABLE org (
+  id int NOT NULL AUTO_INCREMENT,
+  name varchar(32) NOT NULL DEFAULT '',
+  PRIMARY KEY (id)
+);
+CREATE TABLE user_account (
+  id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  org_id int,
+  name varchar(64) NOT NULL DEFAULT '' COMMENT 'a;b "c"',
+  status enum('Active','x') NOT NULL DEFAULT 'Active',
+  updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  parent_id bigint unsigned,
+  UNIQUE KEY uk_org_name (org_id, name),
+  KEY (name),
+  FULLTEXT KEY ft_name (name),
+  CONSTRAINT fk_org FOREIGN KEY (org_id) REFERENCES org (id) ON DELETE SET NULL,
+  FOREIGN KEY (parent_id) REFERENCES user_account (id)
+);
+CREATE TABLE coupon (
+  code varchar(3) NOT NULL DEFAULT '007',
+  used_at timestamp NULL ON UPDATE CURRENT_TIMESTAMP
+);`)
+		So(err, ShouldBeNil)
+		res, err := GenerateGORM(tables, nil)
+		So(err, ShouldBeNil)
+		src := string(res)
+
+		Convey("Columns", func() {
+			So(src, ShouldContainSubstring, "\tID   int32  `db:\"id\" json:\"id\" gorm:\"column:id;type:int;primaryKey;autoIncrement;not null\"`\n")
+			So(src, ShouldContainSubstring, "\tName string `db:\"name\" json:\"name\" gorm:\"column:name;type:varchar(32);not null;default:''\"`\n")
+			So(src, ShouldContainSubstring, "gorm:\"column:status;type:enum('Active','x');not null;default:'Active'\"`\n")
+			So(src, ShouldContainSubstring, "gorm:\"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP\"`\n")
+			// the string default keeps the quotes, ON UPDATE is not AUTO_INCREMENT
+			So(src, ShouldContainSubstring, "gorm:\"column:code;type:varchar(3);not null;default:'007'\"`\n")
+			So(src, ShouldContainSubstring, "gorm:\"column:used_at;type:timestamp\"`\n")
+		})
+
+		Convey("Indexes", func() {
+			So(src, ShouldContainSubstring, "gorm:\"column:org_id;type:int;uniqueIndex:uk_org_name,priority:1\"`\n")
+			So(src, ShouldContainSubstring, "uniqueIndex:uk_org_name,priority:2;index:name;index:ft_name,class:FULLTEXT;not null")
+		})
+
+		Convey("Escape", func() {
+			tag := reflect.StructTag(`gorm:"column:name;comment:a\\;b \"c\""`)
+			So(src, ShouldContainSubstring, `comment:a\\;b \"c\""`)
+			So(tag.Get("gorm"), ShouldEqual, `column:name;comment:a\;b "c"`)
+		})
+
+		Convey("Associations", func() {
+			So(src, ShouldContainSubstring, "\tOrg       *Org              `db:\"-\" json:\"org,omitempty\" gorm:\"foreignKey:OrgID;references:ID;constraint:OnDelete:SET NULL\"`\n")
+			So(src, ShouldContainSubstring, "\tParent    *UserAccount      `db:\"-\" json:\"parent,omitempty\" gorm:\"foreignKey:ParentID;references:ID\"`\n")
+		})
+
+		Convey("TableName", func() {
+			So(src, ShouldContainSubstring, "func (UserAccount) TableName() string {\n\treturn \"user_account\"\n}\n")
+		})
+	})
+}
@@ -0,0 +1,295 @@
+package sqlparser
+
+import (
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+)
+
+// The ways to map the nullable columns of GoOptions
+const (
+	NullableSQL     = "sql"     // sql.NullString, sql.NullInt64 and so on, pointer if no such type
+	NullablePointer = "pointer" // *string, *int64 and so on
+)
+
+// GoOptions is the options of GenerateGo, the zero value is usable
+type GoOptions struct {
+	Package  string // the package name, model if empty
+	Nullable string // NullableSQL if empty
+
+	// Decimal is the Go type of DECIMAL, string if empty, e.g. decimal.Decimal
+	// with DecimalImport github.com/shopspring/decimal
+	Decimal       string
+	DecimalImport string
+}
+
+// GenerateGo generate the gofmt-ed Go source of one struct per table. The fields are
+// named in PascalCase with db and json tags, UNSIGNED are uint types and ENUM are
+// named string types with constants. The table and column comments are doc comments.
+// The columns or types of the same Go name are an error
+func GenerateGo(tables []*Table, opts *GoOptions) ([]byte, error) {
+	g := newGoGenerator(opts)
+	var body strings.Builder
+	for _, t := range tables {
+		if err := g.writeStruct(&body, t); err != nil {
+			return nil, err
+		}
+	}
+	return g.source(body.String())
+}
+
+type goGenerator struct {
+	opts    GoOptions
+	imports map[string]bool
+	// types is the generated type names and what they are generated from
+	types map[string]string
+
+	// tags return the extra struct tags of the column, e.g. gorm
+	tags func(t *Table, col *Column) []string
+	// fields return the extra field lines of the struct, e.g. the associations
+	fields func(t *Table, str *strings.Builder)
+	// methods write the methods after the struct, e.g. TableName
+	methods func(t *Table, str *strings.Builder)
+}
+
+func newGoGenerator(opts *GoOptions) *goGenerator {
+	g := &goGenerator{imports: map[string]bool{}, types: map[string]string{}}
+	if opts != nil {
+		g.opts = *opts
+	}
+	if g.opts.Package == "" {
+		g.opts.Package = "model"
+	}
+	if g.opts.Nullable == "" {
+		g.opts.Nullable = NullableSQL
+	}
+	if g.opts.Decimal == "" {
+		g.opts.Decimal = "string"
+	}
+	return g
+}
+
+// source return the file of the body with the package clause and imports, formatted
+func (g *goGenerator) source(body string) ([]byte, error) {
+	var str strings.Builder
+	str.WriteString("// Code generated by sqlparser. DO NOT EDIT.\n\n")
+	str.WriteString("package " + g.opts.Package + "\n\n")
+	if len(g.imports) != 0 {
+		// the standard packages first, like goimports
+		var std, others []string
+		for path := range g.imports {
+			if strings.Contains(strings.Split(path, "/")[0], ".") {
+				others = append(others, path)
+			} else {
+				std = append(std, path)
+			}
+		}
+		sort.Strings(std)
+		sort.Strings(others)
+		str.WriteString("import (\n")
+		for _, path := range std {
+			str.WriteString(fmt.Sprintf("%q\n", path))
+		}
+		if len(std) != 0 && len(others) != 0 {
+			str.WriteString("\n")
+		}
+		for _, path := range others {
+			str.WriteString(fmt.Sprintf("%q\n", path))
+		}
+		str.WriteString(")\n\n")
+	}
+	str.WriteString(body)
+	res, err := format.Source([]byte(str.String()))
+	if err != nil {
+		return nil, fmt.Errorf("format generated go source: %w", err)
+	}
+	return res, nil
+}
+
+func (g *goGenerator) writeStruct(str *strings.Builder, t *Table) error {
+	name := pascalCase(t.Name, true)
+	if err := g.addType(name, fmt.Sprintf("table '%s'", t.Name)); err != nil {
+		return err
+	}
+	var enums []string
+	fields := map[string]string{}
+	writeComment(str, "", name, tableComment(t))
+	str.WriteString("type " + name + " struct {\n")
+	for _, col := range t.Columns {
+		field := pascalCase(col.Name, true)
+		if other, ok := fields[field]; ok {
+			return fmt.Errorf("table '%s': columns '%s' and '%s' are both the Go field %s", t.Name, other, col.Name, field)
+		}
+		fields[field] = col.Name
+		typ := g.fieldType(t, col)
+		if col.DataType != nil && col.DataType.Name == "ENUM" {
+			if err := g.addType(name+field, fmt.Sprintf("ENUM column '%s' of table '%s'", col.Name, t.Name)); err != nil {
+				return err
+			}
+			enums = append(enums, g.enumSource(name+field, col))
+		}
+		if col.Constraint != nil && col.Constraint.Comment != "" {
+			writeComment(str, "\t", "", col.Constraint.Comment)
+		}
+		tags := []string{fmt.Sprintf(`db:"%s"`, col.Name), fmt.Sprintf(`json:"%s"`, col.Name)}
+		if g.tags != nil {
+			tags = append(tags, g.tags(t, col)...)
+		}
+		str.WriteString(fmt.Sprintf("\t%s %s `%s`\n", field, typ, strings.Join(tags, " ")))
+	}
+	if g.fields != nil {
+		g.fields(t, str)
+	}
+	str.WriteString("}\n\n")
+	if g.methods != nil {
+		g.methods(t, str)
+	}
+	for _, enum := range enums {
+		str.WriteString(enum)
+	}
+	return nil
+}
+
+// addType reserve the type name generated from what, the name of another type is an error
+func (g *goGenerator) addType(name, what string) error {
+	if other, ok := g.types[name]; ok {
+		return fmt.Errorf("%s and %s are both the Go type %s", other, what, name)
+	}
+	g.types[name] = what
+	return nil
+}
+
+// enumSource return the named string type and its constants
+func (g *goGenerator) enumSource(name string, col *Column) string {
+	var str strings.Builder
+	str.WriteString(fmt.Sprintf("// %s is the values of %s\n", name, col.Name))
+	str.WriteString(fmt.Sprintf("type %s string\n\n", name))
+	if len(col.DataType.CollectionOptions) == 0 {
+		return str.String()
+	}
+	str.WriteString("const (\n")
+	used := map[string]bool{}
+	for _, value := range col.DataType.CollectionOptions {
+		constant := name + pascalWords(value, true)
+		if constant == name {
+			constant += "Empty"
+		}
+		for i := 2; used[constant]; i++ {
+			constant = fmt.Sprintf("%s%s%d", name, pascalWords(value, true), i)
+		}
+		used[constant] = true
+		str.WriteString(fmt.Sprintf("\t%s %s = %q\n", constant, name, value))
+	}
+	str.WriteString(")\n\n")
+	return str.String()
+}
+
+// fieldType return the type of the struct field, the named string type of ENUM or goType
+func (g *goGenerator) fieldType(t *Table, col *Column) string {
+	if col.DataType == nil || col.DataType.Name != "ENUM" {
+		return g.goType(t, col)
+	}
+	typ := pascalCase(t.Name, true) + pascalCase(col.Name, true)
+	if columnNullable(t, col) {
+		typ = "*" + typ
+	}
+	return typ
+}
+
+// goType return the Go type of the column, the nullable column is mapped by the Nullable option
+func (g *goGenerator) goType(t *Table, col *Column) string {
+	typ := "string"
+	if col.DataType != nil {
+		typ = g.baseType(col.DataType)
+	}
+	if columnNullable(t, col) && typ != "[]byte" {
+		null, ok := map[string]string{
+			"string": "sql.NullString", "bool": "sql.NullBool", "uint8": "sql.NullByte",
+			"int8": "sql.NullInt16", "int16": "sql.NullInt16", "uint16": "sql.NullInt32",
+			"int32": "sql.NullInt32", "uint32": "sql.NullInt64", "int64": "sql.NullInt64",
+			"float32": "sql.NullFloat64", "float64": "sql.NullFloat64", "time.Time": "sql.NullTime",
+		}[typ]
+		if g.opts.Nullable == NullableSQL && ok {
+			typ = null
+		} else {
+			typ = "*" + typ
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(typ, "sql."):
+		g.imports["database/sql"] = true
+	case strings.TrimPrefix(typ, "*") == "time.Time":
+		g.imports["time"] = true
+	case strings.TrimPrefix(typ, "*") == g.opts.Decimal && g.opts.DecimalImport != "":
+		g.imports[g.opts.DecimalImport] = true
+	}
+	return typ
+}
+
+// baseType return the Go type of the not null data type
+func (g *goGenerator) baseType(dt *DataType) string {
+	if dt.Name == "BOOL" || dt.Name == "BOOLEAN" {
+		return "bool"
+	}
+	name := foreignKeyType(dt)
+	if isIntegerType(name) {
+		bits := map[string]string{"TINYINT": "8", "SMALLINT": "16", "MEDIUMINT": "32", "INT": "32", "BIGINT": "64"}[name]
+		if dt.IsUnsigned {
+			return "uint" + bits
+		}
+		return "int" + bits
+	}
+	switch name {
+	case "FLOAT":
+		return "float32"
+	case "DOUBLE":
+		return "float64"
+	case "DECIMAL":
+		return g.opts.Decimal
+	case "DATE", "DATETIME", "TIMESTAMP":
+		return "time.Time"
+	case "YEAR":
+		return "int16"
+	case "BINARY", "TINYBLOB", "BLOB", "MEDIUMBLOB", "LONGBLOB", "BIT", "GEOMETRY", "POINT", "LINESTRING",
+		"POLYGON", "MULTIPOINT", "MULTILINESTRING", "MULTIPOLYGON", "GEOMETRYCOLLECTION", "GEOMCOLLECTION":
+		return "[]byte"
+	}
+	return "string"
+}
+
+// columnNullable report whether the column may be NULL, the primary key columns are NOT NULL
+func columnNullable(t *Table, col *Column) bool {
+	if col.Constraint != nil && (col.Constraint.NotNull || col.Constraint.Primary || col.Constraint.Key) {
+		return false
+	}
+	if pk := primaryKey(t); pk != nil && containsName(pk.ColumnPrimaryKey, col.Name) {
+		return false
+	}
+	return true
+}
+
+func tableComment(t *Table) string {
+	if t.Options != nil {
+		return t.Options.Comment
+	}
+	return ""
+}
+
+// writeComment write the comment lines, the first one is prefixed by the name
+func writeComment(str *strings.Builder, indent, name, comment string) {
+	if comment == "" {
+		return
+	}
+	for i, line := range strings.Split(comment, "\n") {
+		str.WriteString(indent + "//")
+		if i == 0 && name != "" {
+			line = name + " " + line
+		}
+		if line != "" {
+			str.WriteString(" " + line)
+		}
+		str.WriteString("\n")
+	}
+}
@@ -0,0 +1,99 @@
+package sqlparser
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGenerateGo(t *testing.T) {
+	Convey("TestGenerateGo", t, func() {
+		tables, err := From(`CREATE TABLE user_account (
+  id bigint unsigned NOT NULL AUTO_INCREMENT,
+  org_id int,
+  name varchar(64) NOT NULL COMMENT '用户名',
+  status enum('active','disabled','') NOT NULL DEFAULT 'active',
+  balance decimal(10,2),
+  is_admin bool NOT NULL,
+  avatar blob,
+  created_at datetime NOT NULL,
+  deleted_at timestamp NULL,
+  PRIMARY KEY (id)
+) COMMENT='用户表';`)
+		So(err, ShouldBeNil)
+
+		Convey("Default", func() {
+			res, err := GenerateGo(tables, nil)
+			So(err, ShouldBeNil)
+			So(string(res), ShouldEqual, "// Code generated by sqlparser. DO NOT EDIT.\n\n"+
+				"package model\n\n"+
+				"import (\n\t\"database/sql\"\n\t\"time\"\n)\n\n"+
+				"// UserAccount 用户表\n"+
+				"type UserAccount struct {\n"+
+				"\tID    uint64        `db:\"id\" json:\"id\"`\n"+
+				"\tOrgID sql.NullInt32 `db:\"org_id\" json:\"org_id\"`\n"+
+				"\t// 用户名\n"+
+				"\tName      string            `db:\"name\" json:\"name\"`\n"+
+				"\tStatus    UserAccountStatus `db:\"status\" json:\"status\"`\n"+
+				"\tBalance   sql.NullString    `db:\"balance\" json:\"balance\"`\n"+
+				"\tIsAdmin   bool              `db:\"is_admin\" json:\"is_admin\"`\n"+
+				"\tAvatar    []byte            `db:\"avatar\" json:\"avatar\"`\n"+
+				"\tCreatedAt time.Time         `db:\"created_at\" json:\"created_at\"`\n"+
+				"\tDeletedAt sql.NullTime      `db:\"deleted_at\" json:\"deleted_at\"`\n"+
+				"}\n\n"+
+				"// UserAccountStatus is the values of status\n"+
+				"type UserAccountStatus string\n\n"+
+				"const (\n"+
+				"\tUserAccountStatusActive   UserAccountStatus = \"active\"\n"+
+				"\tUserAccountStatusDisabled UserAccountStatus = \"disabled\"\n"+
+				"\tUserAccountStatusEmpty    UserAccountStatus = \"\"\n"+
+				")\n")
+		})
+
+		Convey("Pointer", func() {
+			res, err := GenerateGo(tables, &GoOptions{
+				Package:       "db",
+				Nullable:      NullablePointer,
+				Decimal:       "decimal.Decimal",
+				DecimalImport: "github.com/shopspring/decimal",
+			})
+			So(err, ShouldBeNil)
+			So(string(res), ShouldContainSubstring, "package db\n\nimport (\n\t\"time\"\n\n\t\"github.com/shopspring/decimal\"\n)\n")
+			So(string(res), ShouldContainSubstring, "\tOrgID *int32 `db:\"org_id\" json:\"org_id\"`\n")
+			So(string(res), ShouldContainSubstring, "\tBalance   *decimal.Decimal  `db:\"balance\" json:\"balance\"`\n")
+			So(string(res), ShouldContainSubstring, "\tDeletedAt *time.Time        `db:\"deleted_at\" json:\"deleted_at\"`\n")
+		})
+
+		Convey("Naming", func() {
+			So(pascalCase("user_id", true), ShouldEqual, "UserID")
+			So(pascalCase("HTTPServerURL", true), ShouldEqual, "HTTPServerURL")
+			So(pascalCase("api-key", false), ShouldEqual, "ApiKey")
+			So(camelCase("created_at"), ShouldEqual, "createdAt")
+			So(camelCase("UserID"), ShouldEqual, "userId")
+			So(pascalCase("2fa", true), ShouldEqual, "X2fa")
+		})
+
+		Convey("Digit", func() {
+			tables, err := From("CREATE TABLE t (id int, `2fa` enum('1', 'on') NOT NULL);")
+			So(err, ShouldBeNil)
+			res, err := GenerateGo(tables, nil)
+			So(err, ShouldBeNil)
+			So(string(res), ShouldContainSubstring, "\tX2fa TX2fa         `db:\"2fa\" json:\"2fa\"`\n")
+			So(string(res), ShouldContainSubstring, "\tTX2fa1  TX2fa = \"1\"\n")
+		})
+
+		Convey("Conflict", func() {
+			tables, err := From("CREATE TABLE t (user_id int, userId int);")
+			So(err, ShouldBeNil)
+			_, err = GenerateGo(tables, nil)
+			So(err, ShouldBeError, "table 't': columns 'user_id' and 'userId' are both the Go field UserID")
+
+			tables, err = From("CREATE TABLE user (id int, status enum('a')); CREATE TABLE user_status (id int);")
+			So(err, ShouldBeNil)
+			_, err = GenerateGo(tables, nil)
+			So(err, ShouldBeError, "ENUM column 'status' of table 'user' and table 'user_status' are both the Go type UserStatus")
+			_, err = GenerateGORM(tables, nil)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
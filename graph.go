@@ -0,0 +1,234 @@
+package sqlparser
+
+import (
+	"sort"
+	"strings"
+)
+
+// Dependency is a foreign key of Table referencing Referenced
+type Dependency struct {
+	Table      *Table
+	Constraint *TableConstraint
+	Referenced *Table
+}
+
+// Nullable report whether the foreign key columns are all nullable,
+// so the rows can be inserted with NULL first and updated after
+func (d *Dependency) Nullable() bool {
+	for _, name := range d.Constraint.ColumnForeignKey {
+		if col := d.Table.Column(name); col == nil || col.Constraint != nil && col.Constraint.NotNull {
+			return false
+		}
+	}
+	return true
+}
+
+// DependencyGraph is the foreign key dependencies between the tables,
+// the foreign keys referencing a table not in the graph are ignored
+type DependencyGraph struct {
+	Tables       []*Table
+	Dependencies []*Dependency
+
+	// deferred is the foreign keys removed to break the cycles
+	deferred map[*Dependency]bool
+}
+
+// NewDependencyGraph build the graph of the tables, e.g. From or Catalog.Tables.
+// The unqualified referenced table is in the schema of the table
+func NewDependencyGraph(tables []*Table) *DependencyGraph {
+	g := &DependencyGraph{Tables: tables}
+	for _, t := range tables {
+		for _, cons := range t.Constraints {
+			if !isForeignKey(cons) || cons.Reference == nil || cons.Reference.Table == nil {
+				continue
+			}
+			schema := cons.Reference.Table.Schema
+			if schema == "" {
+				schema = t.Schema
+			}
+			for _, ref := range tables {
+				if strings.EqualFold(ref.Schema, schema) && strings.EqualFold(ref.Name, cons.Reference.Table.Name) {
+					g.Dependencies = append(g.Dependencies, &Dependency{Table: t, Constraint: cons, Referenced: ref})
+					break
+				}
+			}
+		}
+	}
+	g.deferred = g.breakCycles()
+	return g
+}
+
+// Order return the tables in the order to create and insert, the referenced tables first.
+// The deferred foreign keys are ignored, so the cyclic tables are ordered too
+func (g *DependencyGraph) Order() []*Table {
+	index := map[*Table]int{}
+	for i, t := range g.Tables {
+		index[t] = i
+	}
+	indegree := make([]int, len(g.Tables))
+	children := make([][]int, len(g.Tables))
+	for _, dep := range g.Dependencies {
+		if g.deferred[dep] || dep.Table == dep.Referenced {
+			continue
+		}
+		from, to := index[dep.Referenced], index[dep.Table]
+		children[from] = append(children[from], to)
+		indegree[to]++
+	}
+
+	// Kahn's algorithm, the ready tables are taken in the given order
+	var res []*Table
+	var ready []int
+	for i := range g.Tables {
+		if indegree[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+	for len(ready) != 0 {
+		sort.Ints(ready)
+		i := ready[0]
+		ready = ready[1:]
+		res = append(res, g.Tables[i])
+		for _, j := range children[i] {
+			if indegree[j]--; indegree[j] == 0 {
+				ready = append(ready, j)
+			}
+		}
+	}
+	return res
+}
+
+// DropOrder return the tables in the order to drop or delete, the referencing tables first
+func (g *DependencyGraph) DropOrder() []*Table {
+	res := g.Order()
+	for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+		res[i], res[j] = res[j], res[i]
+	}
+	return res
+}
+
+// Cycles return the strongly connected components of the cyclic foreign keys,
+// including the table referencing itself
+func (g *DependencyGraph) Cycles() [][]*Table {
+	var res [][]*Table
+	for _, component := range g.components(nil) {
+		if len(component) > 1 {
+			res = append(res, component)
+			continue
+		}
+		for _, dep := range g.Dependencies {
+			if dep.Table == component[0] && dep.Referenced == component[0] {
+				res = append(res, component)
+				break
+			}
+		}
+	}
+	return res
+}
+
+// Deferred return the foreign keys to defer to break the cycles, e.g. added after the
+// rows are loaded, or inserted as NULL and updated after. The nullable ones are preferred
+func (g *DependencyGraph) Deferred() []*Dependency {
+	var res []*Dependency
+	for _, dep := range g.Dependencies {
+		if g.deferred[dep] {
+			res = append(res, dep)
+		}
+	}
+	return res
+}
+
+// breakCycles remove a foreign key of every cyclic component until no cycle is left
+func (g *DependencyGraph) breakCycles() map[*Dependency]bool {
+	removed := map[*Dependency]bool{}
+	for _, dep := range g.Dependencies {
+		if dep.Table == dep.Referenced {
+			removed[dep] = true
+		}
+	}
+	for {
+		changed := false
+		for _, component := range g.components(removed) {
+			if len(component) < 2 {
+				continue
+			}
+			in := map[*Table]bool{}
+			for _, t := range component {
+				in[t] = true
+			}
+			var candidate *Dependency
+			for _, dep := range g.Dependencies {
+				if removed[dep] || !in[dep.Table] || !in[dep.Referenced] {
+					continue
+				}
+				if candidate == nil || dep.Nullable() && !candidate.Nullable() {
+					candidate = dep
+				}
+			}
+			removed[candidate] = true
+			changed = true
+		}
+		if !changed {
+			return removed
+		}
+	}
+}
+
+// components return the strongly connected components by Tarjan's algorithm,
+// the removed foreign keys are ignored
+func (g *DependencyGraph) components(removed map[*Dependency]bool) [][]*Table {
+	edges := map[*Table][]*Table{}
+	for _, dep := range g.Dependencies {
+		if !removed[dep] {
+			edges[dep.Table] = append(edges[dep.Table], dep.Referenced)
+		}
+	}
+
+	var res [][]*Table
+	index, low := map[*Table]int{}, map[*Table]int{}
+	onStack := map[*Table]bool{}
+	var stack []*Table
+	var visit func(t *Table)
+	visit = func(t *Table) {
+		index[t], low[t] = len(index), len(index)
+		stack = append(stack, t)
+		onStack[t] = true
+		for _, next := range edges[t] {
+			if _, ok := index[next]; !ok {
+				visit(next)
+				low[t] = min(low[t], low[next])
+			} else if onStack[next] {
+				low[t] = min(low[t], index[next])
+			}
+		}
+		if low[t] == index[t] {
+			var component []*Table
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component = append(component, top)
+				if top == t {
+					break
+				}
+			}
+			res = append(res, component)
+		}
+	}
+	for _, t := range g.Tables {
+		if _, ok := index[t]; !ok {
+			visit(t)
+		}
+	}
+
+	// the tables of component in the given order
+	order := map[*Table]int{}
+	for i, t := range g.Tables {
+		order[t] = i
+	}
+	for _, component := range res {
+		sort.Slice(component, func(i, j int) bool { return order[component[i]] < order[component[j]] })
+	}
+	sort.Slice(res, func(i, j int) bool { return order[res[i][0]] < order[res[j][0]] })
+	return res
+}
@@ -0,0 +1,65 @@
+package sqlparser
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDependencyGraph(t *testing.T) {
+	Convey("TestDependencyGraph", t, func() {
+		c := new(Catalog)
+		names := func(tables []*Table) []string {
+			var res []string
+			for _, t := range tables {
+				res = append(res, t.Name)
+			}
+			return res
+		}
+
+		Convey("Acyclic", func() {
+			So(replay(c, `CREATE TABLE item (id int PRIMARY KEY, order_id int, product_id int,
+  FOREIGN KEY (order_id) REFERENCES orders (id), FOREIGN KEY (product_id) REFERENCES product (id));
+CREATE TABLE orders (id int PRIMARY KEY, user_id int, FOREIGN KEY (user_id) REFERENCES user (id));
+CREATE TABLE product (id int PRIMARY KEY);
+CREATE TABLE User (id int PRIMARY KEY, FOREIGN KEY (id) REFERENCES external (id));`), ShouldBeNil)
+
+			g := NewDependencyGraph(c.Tables())
+			So(len(g.Dependencies), ShouldEqual, 3)
+			// the referenced table names are case insensitive
+			So(names(g.Order()), ShouldResemble, []string{"product", "User", "orders", "item"})
+			So(names(g.DropOrder()), ShouldResemble, []string{"item", "orders", "User", "product"})
+			So(g.Cycles(), ShouldBeEmpty)
+			So(g.Deferred(), ShouldBeEmpty)
+		})
+
+		Convey("Cyclic", func() {
+			So(replay(c, `CREATE TABLE dept (id int PRIMARY KEY, manager_id int NOT NULL,
+  CONSTRAINT fk_manager FOREIGN KEY (manager_id) REFERENCES employee (id));
+CREATE TABLE employee (id int PRIMARY KEY, dept_id int, boss_id int,
+  CONSTRAINT fk_dept FOREIGN KEY (dept_id) REFERENCES dept (id),
+  CONSTRAINT fk_boss FOREIGN KEY (boss_id) REFERENCES employee (id));
+CREATE TABLE project (id int PRIMARY KEY, dept_id int NOT NULL, FOREIGN KEY (dept_id) REFERENCES dept (id));
+CREATE TABLE a (id int PRIMARY KEY, b_id int NOT NULL, CONSTRAINT fk_b FOREIGN KEY (b_id) REFERENCES b (id));
+CREATE TABLE b (id int PRIMARY KEY, a_id int NOT NULL, CONSTRAINT fk_a FOREIGN KEY (a_id) REFERENCES a (id));`), ShouldBeNil)
+
+			g := NewDependencyGraph(c.Tables())
+			var cycles [][]string
+			for _, cycle := range g.Cycles() {
+				cycles = append(cycles, names(cycle))
+			}
+			So(cycles, ShouldResemble, [][]string{{"dept", "employee"}, {"a", "b"}})
+
+			var deferred []string
+			for _, dep := range g.Deferred() {
+				deferred = append(deferred, dep.Constraint.Name)
+			}
+			// the nullable fk_dept is preferred to the NOT NULL fk_manager
+			So(deferred, ShouldResemble, []string{"fk_dept", "fk_boss", "fk_b"})
+			So(g.Deferred()[0].Nullable(), ShouldBeTrue)
+
+			So(names(g.Order()), ShouldResemble, []string{"employee", "dept", "project", "a", "b"})
+			So(names(g.DropOrder()), ShouldResemble, []string{"b", "a", "project", "dept", "employee"})
+		})
+	})
+}
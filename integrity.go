@@ -0,0 +1,285 @@
+package sqlparser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IntegrityError is a foreign key MySQL would refuse when applied,
+// usually reported as errno 150 "Foreign key constraint is incorrectly formed"
+type IntegrityError struct {
+	Table      *TableName
+	Constraint string
+	Message    string
+}
+
+func (e *IntegrityError) Error() string {
+	name := e.Table.Name
+	if e.Table.Schema != "" {
+		name = e.Table.Schema + "." + name
+	}
+	return fmt.Sprintf("foreign key '%s' of table '%s': %s", e.Constraint, name, e.Message)
+}
+
+// CheckForeignKeys check every foreign key of the catalog: the referenced table and columns exist,
+// the column types are compatible, both sides have an index starting with the columns,
+// and SET NULL is not used on the NOT NULL columns. The referenced table is looked up
+// in the schema of the table if not qualified
+func (c *Catalog) CheckForeignKeys() []*IntegrityError {
+	var res []*IntegrityError
+	for _, s := range c.Schemas {
+		for _, t := range s.Tables {
+			for _, fk := range t.Constraints {
+				if !isForeignKey(fk) || fk.Reference == nil {
+					continue
+				}
+				for _, msg := range c.checkForeignKey(s, t, fk) {
+					res = append(res, &IntegrityError{Table: tableName(t), Constraint: fk.Name, Message: msg})
+				}
+			}
+		}
+	}
+	return res
+}
+
+func (c *Catalog) checkForeignKey(s *Schema, t *Table, fk *TableConstraint) []string {
+	var res []string
+	ref := fk.Reference
+	schema := ref.Table.Schema
+	if schema == "" {
+		schema = t.Schema
+	}
+	parent := c.Table(schema, ref.Table.Name)
+	if parent == nil {
+		name := ref.Table.Name
+		if schema != "" {
+			name = schema + "." + name
+		}
+		return []string{fmt.Sprintf("referenced table '%s' doesn't exist", name)}
+	}
+	if len(fk.ColumnForeignKey) != len(ref.Columns) {
+		return []string{fmt.Sprintf("the number of columns (%d) doesn't match the referenced columns (%d)",
+			len(fk.ColumnForeignKey), len(ref.Columns))}
+	}
+
+	for i, name := range fk.ColumnForeignKey {
+		col := t.Column(name)
+		if col == nil {
+			res = append(res, fmt.Sprintf("column '%s' doesn't exist", name))
+			continue
+		}
+		refCol := parent.Column(ref.Columns[i])
+		if refCol == nil {
+			res = append(res, fmt.Sprintf("referenced column '%s' doesn't exist in table '%s'", ref.Columns[i], parent.Name))
+			continue
+		}
+		if msg := compatibleColumns(columnCharset(s, t, col), columnCharset(c.Schema(parent.Schema), parent, refCol)); msg != "" {
+			res = append(res, msg)
+		}
+		if col.Constraint != nil && col.Constraint.NotNull {
+			for _, action := range []struct{ event, value string }{{"DELETE", ref.OnDelete}, {"UPDATE", ref.OnUpdate}} {
+				if action.value == "SET NULL" {
+					res = append(res, fmt.Sprintf("ON %s SET NULL on the NOT NULL column '%s'", action.event, col.Name))
+				}
+			}
+		}
+	}
+
+	if leadingIndex(t, fk.ColumnForeignKey, nil) == nil {
+		res = append(res, fmt.Sprintf("missing index on the columns (%s)", strings.Join(fk.ColumnForeignKey, ", ")))
+	}
+	if leadingIndex(parent, ref.Columns, nil) == nil {
+		res = append(res, fmt.Sprintf("missing index on the referenced columns (%s) of table '%s'",
+			strings.Join(ref.Columns, ", "), parent.Name))
+	}
+	return res
+}
+
+// typedColumn is a column with the resolved charset and collation, empty if unknown
+type typedColumn struct {
+	*Column
+	charset   string
+	collation string
+}
+
+// columnCharset resolve the charset and collation of the character column
+// by the column, the table, the database and the server charset in turn
+func columnCharset(s *Schema, t *Table, col *Column) typedColumn {
+	res := typedColumn{Column: col}
+	if col.DataType == nil || !isCharacterType(col.DataType) {
+		return res
+	}
+	res.charset, res.collation = tableCharset(s, t)
+	dt := col.DataType
+	if dt.IsNational || dt.IsNChar || dt.Name == "NCHAR" || dt.Name == "NVARCHAR" {
+		res.charset, res.collation = mergeCharset(res.charset, res.collation, "utf8mb3", "")
+	}
+	res.charset, res.collation = mergeCharset(res.charset, res.collation, dt.CharsetName, dt.CollationName)
+	if dt.IsBinary && res.charset != "" && dt.CollationName == "" {
+		res.collation = normalizeCharset(res.charset) + "_bin"
+	}
+	return res
+}
+
+// tableCharset resolve the default charset and collation of the table
+// by the table, the database and the server charset in turn
+func tableCharset(s *Schema, t *Table) (string, string) {
+	var charset, collation string
+	// the charset of the client session is of the literals, not of the tables
+	if t.Session != nil {
+		charset, collation = mergeCharset(charset, collation, t.Session.CharacterSetServer, t.Session.CollationServer)
+		charset, collation = mergeCharset(charset, collation, t.Session.CharacterSetDatabase, t.Session.CollationDatabase)
+	}
+	if s != nil && s.Database != nil {
+		charset, collation = mergeCharset(charset, collation, s.Database.Charset, s.Database.Collation)
+	}
+	if t.Options != nil {
+		charset, collation = mergeCharset(charset, collation, t.Options.Charset, t.Options.Collation)
+	}
+	return charset, collation
+}
+
+// mergeCharset return the charset and collation overridden by the specified ones,
+// the collation of another charset doesn't apply
+func mergeCharset(charset, collation, newCharset, newCollation string) (string, string) {
+	if newCharset == "DEFAULT" {
+		newCharset = ""
+	}
+	if newCharset == "" && newCollation != "" {
+		newCharset = collationCharset(newCollation)
+	}
+	if newCharset == "" {
+		return charset, collation
+	}
+	if !strings.EqualFold(normalizeCharset(newCharset), normalizeCharset(charset)) {
+		collation = ""
+	}
+	if newCollation != "" {
+		collation = newCollation
+	}
+	return newCharset, collation
+}
+
+// compatibleColumns return why the column can't reference the other column, empty if compatible.
+// Like InnoDB, the integer and decimal types must be the same in size and sign,
+// the length of the string types may differ but not the charset and collation
+func compatibleColumns(a, b typedColumn) string {
+	if a.DataType == nil || b.DataType == nil {
+		return ""
+	}
+	ta, tb := foreignKeyType(a.DataType), foreignKeyType(b.DataType)
+	if ta != tb {
+		return fmt.Sprintf("column '%s' %s is incompatible with the referenced column '%s' %s",
+			a.Name, a.DataType.Source, b.Name, b.DataType.Source)
+	}
+	if isIntegerType(ta) || ta == "DECIMAL" {
+		if a.DataType.IsUnsigned != b.DataType.IsUnsigned {
+			sign := func(dt *DataType) string {
+				if dt.IsUnsigned {
+					return "unsigned"
+				}
+				return "signed"
+			}
+			return fmt.Sprintf("column '%s' is %s but the referenced column '%s' is %s",
+				a.Name, sign(a.DataType), b.Name, sign(b.DataType))
+		}
+	}
+	if ta == "DECIMAL" {
+		pa, sa := decimalPrecision(a.DataType)
+		pb, sb := decimalPrecision(b.DataType)
+		if pa != pb || sa != sb {
+			return fmt.Sprintf("column '%s' DECIMAL(%d,%d) differs in precision from the referenced column '%s' DECIMAL(%d,%d)",
+				a.Name, pa, sa, b.Name, pb, sb)
+		}
+	}
+	if ta == "CHAR" {
+		if a.charset != "" && b.charset != "" && !strings.EqualFold(normalizeCharset(a.charset), normalizeCharset(b.charset)) {
+			return fmt.Sprintf("column '%s' charset %s differs from the referenced column '%s' charset %s",
+				a.Name, a.charset, b.Name, b.charset)
+		}
+		if a.collation != "" && b.collation != "" && !strings.EqualFold(normalizeCollation(a.collation), normalizeCollation(b.collation)) {
+			return fmt.Sprintf("column '%s' collation %s differs from the referenced column '%s' collation %s",
+				a.Name, a.collation, b.Name, b.collation)
+		}
+	}
+	return ""
+}
+
+// foreignKeyType return the type compared by foreign key, the aliases are the same type,
+// CHAR and VARCHAR are CHAR, BINARY and VARBINARY are BINARY
+func foreignKeyType(dt *DataType) string {
+	switch dt.Name {
+	case "INTEGER", "INT4":
+		return "INT"
+	case "BOOL", "BOOLEAN", "INT1":
+		return "TINYINT"
+	case "INT2":
+		return "SMALLINT"
+	case "INT3", "MIDDLEINT":
+		return "MEDIUMINT"
+	case "INT8":
+		return "BIGINT"
+	case "DEC", "NUMERIC", "FIXED":
+		return "DECIMAL"
+	case "REAL", "FLOAT8":
+		return "DOUBLE"
+	case "FLOAT4":
+		return "FLOAT"
+	case "CHARACTER", "VARCHAR", "NCHAR", "NVARCHAR", "VARCHARACTER":
+		return "CHAR"
+	case "VARBINARY":
+		return "BINARY"
+	}
+	return dt.Name
+}
+
+func isIntegerType(name string) bool {
+	switch name {
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "BIGINT":
+		return true
+	}
+	return false
+}
+
+func isCharacterType(dt *DataType) bool {
+	switch foreignKeyType(dt) {
+	case "CHAR", "TINYTEXT", "TEXT", "MEDIUMTEXT", "LONGTEXT", "LONG", "ENUM", "SET":
+		return true
+	}
+	return false
+}
+
+// decimalPrecision return the precision and scale, DECIMAL is DECIMAL(10,0) and DECIMAL(M) is DECIMAL(M,0)
+func decimalPrecision(dt *DataType) (int, int) {
+	switch {
+	case dt.HasTwoLength:
+		return dt.Len1, dt.Len2
+	case dt.HasLength:
+		return dt.Length, 0
+	}
+	return 10, 0
+}
+
+// collationCharset return the charset of the collation, e.g. utf8mb4 of utf8mb4_general_ci
+func collationCharset(collation string) string {
+	if i := strings.Index(collation, "_"); i > 0 {
+		return collation[:i]
+	}
+	return collation
+}
+
+// normalizeCharset treat utf8 as utf8mb3 like MySQL 8.0
+func normalizeCharset(charset string) string {
+	if strings.EqualFold(charset, "utf8") {
+		return "utf8mb3"
+	}
+	return strings.ToLower(charset)
+}
+
+func normalizeCollation(collation string) string {
+	collation = strings.ToLower(collation)
+	if strings.HasPrefix(collation, "utf8_") {
+		return "utf8mb3_" + strings.TrimPrefix(collation, "utf8_")
+	}
+	return collation
+}
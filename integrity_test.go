@@ -0,0 +1,94 @@
+package sqlparser
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCheckForeignKeys(t *testing.T) {
+	Convey("TestCheckForeignKeys", t, func() {
+		c := new(Catalog)
+		messages := func() []string {
+			var res []string
+			for _, err := range c.CheckForeignKeys() {
+				res = append(res, err.Error())
+			}
+			return res
+		}
+
+		Convey("Valid", func() {
+			err := replay(c, `CREATE DATABASE app CHARACTER SET utf8mb4; USE app;
+CREATE TABLE org (id int unsigned PRIMARY KEY, code char(8) COLLATE utf8mb4_bin, price decimal(10,2), UNIQUE KEY (code));
+CREATE TABLE user (id int PRIMARY KEY, org_id integer unsigned NULL, org_code varchar(16) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin,
+  FOREIGN KEY (org_id) REFERENCES org (id) ON DELETE SET NULL,
+  FOREIGN KEY (org_code) REFERENCES app.org (code));`)
+			So(err, ShouldBeNil)
+			So(messages(), ShouldBeEmpty)
+		})
+
+		Convey("Missing", func() {
+			err := replay(c, `SET FOREIGN_KEY_CHECKS = 0;
+CREATE TABLE org (id int PRIMARY KEY, name varchar(8));
+CREATE TABLE user (id int, org_id int, org_name varchar(8), team_id int,
+  CONSTRAINT fk_team FOREIGN KEY (team_id) REFERENCES team (id),
+  CONSTRAINT fk_org FOREIGN KEY (org_id) REFERENCES org (uid),
+  CONSTRAINT fk_org2 FOREIGN KEY (org_id, org_name) REFERENCES org (id),
+  CONSTRAINT fk_name FOREIGN KEY (org_name) REFERENCES org (name));`)
+			So(err, ShouldBeNil)
+			So(messages(), ShouldResemble, []string{
+				"foreign key 'fk_team' of table 'user': referenced table 'team' doesn't exist",
+				"foreign key 'fk_org' of table 'user': referenced column 'uid' doesn't exist in table 'org'",
+				"foreign key 'fk_org' of table 'user': missing index on the referenced columns (uid) of table 'org'",
+				"foreign key 'fk_org2' of table 'user': the number of columns (2) doesn't match the referenced columns (1)",
+				"foreign key 'fk_name' of table 'user': missing index on the referenced columns (name) of table 'org'",
+			})
+		})
+
+		Convey("Incompatible", func() {
+			err := replay(c, `CREATE TABLE org (id int PRIMARY KEY, big bigint, amount decimal(10,2), code varchar(8),
+  KEY (big), KEY (amount), KEY (code)) DEFAULT CHARSET=latin1;
+CREATE TABLE user (id int unsigned NOT NULL, big int, amount decimal(12,2), code varchar(8), label char(8) COLLATE latin1_bin,
+  CONSTRAINT fk_id FOREIGN KEY (id) REFERENCES org (id) ON DELETE SET NULL ON UPDATE SET NULL,
+  CONSTRAINT fk_big FOREIGN KEY (big) REFERENCES org (big),
+  CONSTRAINT fk_amount FOREIGN KEY (amount) REFERENCES org (amount),
+  CONSTRAINT fk_code FOREIGN KEY (code) REFERENCES org (code),
+  CONSTRAINT fk_label FOREIGN KEY (label) REFERENCES org (code)) DEFAULT CHARSET=utf8mb4;`)
+			So(err, ShouldBeNil)
+			So(messages(), ShouldResemble, []string{
+				"foreign key 'fk_id' of table 'user': column 'id' is unsigned but the referenced column 'id' is signed",
+				"foreign key 'fk_id' of table 'user': ON DELETE SET NULL on the NOT NULL column 'id'",
+				"foreign key 'fk_id' of table 'user': ON UPDATE SET NULL on the NOT NULL column 'id'",
+				"foreign key 'fk_big' of table 'user': column 'big' INT is incompatible with the referenced column 'big' BIGINT",
+				"foreign key 'fk_amount' of table 'user': column 'amount' DECIMAL(12,2) differs in precision from the referenced column 'amount' DECIMAL(10,2)",
+				"foreign key 'fk_code' of table 'user': column 'code' charset utf8mb4 differs from the referenced column 'code' charset latin1",
+			})
+			So(c.CheckForeignKeys()[0].Table, ShouldResemble, &TableName{Name: "user"})
+
+			// the column collation wins, latin1_bin is not the default collation of the referenced column
+			err = replay(c, "ALTER TABLE org MODIFY code varchar(8) COLLATE latin1_general_ci")
+			So(err, ShouldBeNil)
+			So(messages()[len(messages())-1], ShouldEqual, "foreign key 'fk_label' of table 'user': "+
+				"column 'label' collation latin1_bin differs from the referenced column 'code' collation latin1_general_ci")
+
+			// CONVERT TO changes the character columns
+			err = replay(c, "ALTER TABLE user CONVERT TO CHARACTER SET latin1 COLLATE latin1_general_ci")
+			So(err, ShouldBeNil)
+			So(messages(), ShouldHaveLength, 5)
+		})
+
+		Convey("Session charset", func() {
+			// the tables without charset are created in the server charset, not of SET NAMES
+			err := replay(c, `SET character_set_server = latin1; SET NAMES utf8mb4;
+CREATE TABLE org (code varchar(8), KEY (code));
+SET character_set_server = utf8mb4; SET NAMES latin1;
+CREATE TABLE user (code varchar(8), name varchar(8) CHARACTER SET latin1,
+  CONSTRAINT fk_code FOREIGN KEY (code) REFERENCES org (code),
+  CONSTRAINT fk_name FOREIGN KEY (name) REFERENCES org (code));`)
+			So(err, ShouldBeNil)
+			So(messages(), ShouldResemble, []string{
+				"foreign key 'fk_code' of table 'user': column 'code' charset utf8mb4 differs from the referenced column 'code' charset latin1",
+			})
+		})
+	})
+}
@@ -0,0 +1,211 @@
+package sqlparser
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// JSONSchemaDialect is the $schema of the generated JSON Schema
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema is a JSON Schema with the keywords generated from the tables.
+// The numbers are json.Number to keep the range of BIGINT UNSIGNED and DECIMAL
+type JSONSchema struct {
+	Schema      string `json:"$schema,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	// Type is a string, or the types with "null" if nullable, empty for any value
+	Type                 interface{}            `json:"type,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+
+	Enum            []interface{} `json:"enum,omitempty"`
+	Format          string        `json:"format,omitempty"`
+	Pattern         string        `json:"pattern,omitempty"`
+	MaxLength       int64         `json:"maxLength,omitempty"`
+	ContentEncoding string        `json:"contentEncoding,omitempty"`
+	Minimum         json.Number   `json:"minimum,omitempty"`
+	Maximum         json.Number   `json:"maximum,omitempty"`
+
+	Default  interface{} `json:"default,omitempty"` // nil if no default
+	ReadOnly bool        `json:"readOnly,omitempty"`
+}
+
+// NewJSONSchema return the JSON Schema of the row of table. The NOT NULL columns without
+// default are required, except the AUTO_INCREMENT and generated ones, the nullable columns accept null
+func NewJSONSchema(t *Table) *JSONSchema {
+	additional := false
+	res := &JSONSchema{
+		Schema:               JSONSchemaDialect,
+		Title:                t.Name,
+		Description:          tableComment(t),
+		Type:                 "object",
+		Properties:           map[string]*JSONSchema{},
+		AdditionalProperties: &additional,
+	}
+	for _, col := range t.Columns {
+		res.Properties[col.Name] = columnJSONSchema(t, col)
+		if columnRequired(t, col) {
+			res.Required = append(res.Required, col.Name)
+		}
+	}
+	return res
+}
+
+// columnJSONSchema return the schema of the column with the comment, null is accepted if nullable
+func columnJSONSchema(t *Table, col *Column) *JSONSchema {
+	s := dataTypeJSONSchema(col.DataType)
+	if s.MaxLength != 0 && byteLimited(col.DataType) {
+		// the characters of the longest encoding fit in the bytes
+		s.MaxLength /= int64(charsetMaxBytes(columnCharset(nil, t, col).charset, ""))
+	}
+	if col.Constraint != nil {
+		s.Description = col.Constraint.Comment
+	}
+	if columnNullable(t, col) {
+		if s.Type != nil {
+			s.Type = []string{s.Type.(string), "null"}
+		}
+		if s.Enum != nil {
+			s.Enum = append(s.Enum, nil)
+		}
+	}
+	return s
+}
+
+// columnRequired report whether the value of column must be given to insert a row
+func columnRequired(t *Table, col *Column) bool {
+	cons := col.Constraint
+	if cons == nil {
+		cons = &ColumnConstraint{}
+	}
+	return !columnNullable(t, col) && !cons.AutoIncrement && cons.Generated == "" &&
+		(cons.DefaultValue == nil || !cons.DefaultValue.Is)
+}
+
+// GenerateJSONSchema return the indented JSON Schema of the table, ending with a newline
+func GenerateJSONSchema(t *Table) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(NewJSONSchema(t)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// integerRanges is the minimum and maximum of the signed and unsigned integer types
+var integerRanges = map[string][3]string{
+	"TINYINT":   {"-128", "127", "255"},
+	"SMALLINT":  {"-32768", "32767", "65535"},
+	"MEDIUMINT": {"-8388608", "8388607", "16777215"},
+	"INT":       {"-2147483648", "2147483647", "4294967295"},
+	"BIGINT":    {"-9223372036854775808", "9223372036854775807", "18446744073709551615"},
+}
+
+// the patterns of the DATETIME, TIMESTAMP and TIME values, the format date-time and time of
+// RFC 3339 require the offset which MySQL does not output
+const (
+	dateTimePattern = `^\d{4}-\d{2}-\d{2}[ T]\d{2}:\d{2}:\d{2}(\.\d{1,6})?(Z|[+-]\d{2}:\d{2})?$`
+	timePattern     = `^-?\d{2,3}:\d{2}:\d{2}(\.\d{1,6})?$`
+)
+
+// byteLimited report whether the length of the character type is in bytes, not characters,
+// like TEXT but not TEXT(n) which is in characters
+func byteLimited(dt *DataType) bool {
+	switch foreignKeyType(dt) {
+	case "TINYTEXT", "MEDIUMTEXT", "LONGTEXT", "LONG":
+		return true
+	case "TEXT":
+		return !dt.HasLength
+	}
+	return false
+}
+
+// dataTypeJSONSchema return the schema of the not null value of the data type
+func dataTypeJSONSchema(dt *DataType) *JSONSchema {
+	if dt == nil {
+		return &JSONSchema{}
+	}
+	if dt.Name == "BOOL" || dt.Name == "BOOLEAN" {
+		return &JSONSchema{Type: "boolean"}
+	}
+	name := foreignKeyType(dt)
+	if r, ok := integerRanges[name]; ok {
+		if dt.IsUnsigned {
+			return &JSONSchema{Type: "integer", Minimum: "0", Maximum: json.Number(r[2])}
+		}
+		return &JSONSchema{Type: "integer", Minimum: json.Number(r[0]), Maximum: json.Number(r[1])}
+	}
+
+	switch name {
+	case "FLOAT", "DOUBLE":
+		s := &JSONSchema{Type: "number"}
+		if dt.IsUnsigned {
+			s.Minimum = "0"
+		}
+		return s
+	case "DECIMAL":
+		precision, scale := decimalPrecision(dt)
+		max := strings.Repeat("9", precision-scale)
+		if max == "" {
+			max = "0"
+		}
+		if scale > 0 {
+			max += "." + strings.Repeat("9", scale)
+		}
+		min := "-" + max
+		if dt.IsUnsigned {
+			min = "0"
+		}
+		return &JSONSchema{Type: "number", Minimum: json.Number(min), Maximum: json.Number(max)}
+	case "BIT":
+		bits := 1
+		if dt.HasLength {
+			bits = dt.Length
+		}
+		return &JSONSchema{Type: "integer", Minimum: "0", Maximum: json.Number(bitsMaximum(bits))}
+	case "YEAR":
+		return &JSONSchema{Type: "integer", Minimum: "1901", Maximum: "2155"}
+	case "DATE":
+		return &JSONSchema{Type: "string", Format: "date"}
+	case "DATETIME", "TIMESTAMP":
+		return &JSONSchema{Type: "string", Pattern: dateTimePattern}
+	case "TIME":
+		return &JSONSchema{Type: "string", Pattern: timePattern}
+	case "ENUM":
+		s := &JSONSchema{Type: "string"}
+		for _, option := range dt.CollectionOptions {
+			s.Enum = append(s.Enum, option)
+		}
+		return s
+	case "JSON":
+		return &JSONSchema{}
+	}
+	switch typeKind(name) {
+	case "character":
+		return &JSONSchema{Type: "string", MaxLength: stringCapacity(name, dt, 1)}
+	case "binary":
+		return &JSONSchema{Type: "string", ContentEncoding: "base64"}
+	}
+	switch name {
+	case "GEOMETRY", "POINT", "LINESTRING", "POLYGON", "MULTIPOINT", "MULTILINESTRING", "MULTIPOLYGON",
+		"GEOMETRYCOLLECTION", "GEOMCOLLECTION":
+		return &JSONSchema{Type: "string", ContentEncoding: "base64"}
+	}
+	return &JSONSchema{Type: "string"}
+}
+
+// bitsMaximum return 2^bits-1 in decimal
+func bitsMaximum(bits int) string {
+	if bits >= 64 {
+		return strconv.FormatUint(math.MaxUint64, 10)
+	}
+	return strconv.FormatUint(1<<uint(bits)-1, 10)
+}
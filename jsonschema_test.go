@@ -0,0 +1,136 @@
+package sqlparser
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGenerateJSONSchema(t *testing.T) {
+	Convey("TestGenerateJSONSchema", t, func() {
+		tables, err := From(`CREATE TABLE user_account (
+  id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  age tinyint unsigned NOT NULL,
+  name varchar(64) NOT NULL COMMENT '用户名',
+  bio text,
+  note text CHARACTER SET latin1,
+  summary text(100),
+  status enum('active','disabled') DEFAULT 'active',
+  balance decimal(10,2) NOT NULL DEFAULT 0,
+  flags bit(3),
+  is_admin bool NOT NULL,
+  birthday date,
+  created_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  avatar blob,
+  extra json,
+  opens_at time(3)
+) DEFAULT CHARSET=utf8mb4 COMMENT 'users';`)
+		So(err, ShouldBeNil)
+		res, err := GenerateJSONSchema(tables[0])
+		So(err, ShouldBeNil)
+		So(string(res), ShouldEqual, `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "user_account",
+  "description": "users",
+  "type": "object",
+  "properties": {
+    "age": {
+      "type": "integer",
+      "minimum": 0,
+      "maximum": 255
+    },
+    "avatar": {
+      "type": [
+        "string",
+        "null"
+      ],
+      "contentEncoding": "base64"
+    },
+    "balance": {
+      "type": "number",
+      "minimum": -99999999.99,
+      "maximum": 99999999.99
+    },
+    "bio": {
+      "type": [
+        "string",
+        "null"
+      ],
+      "maxLength": 16383
+    },
+    "birthday": {
+      "type": [
+        "string",
+        "null"
+      ],
+      "format": "date"
+    },
+    "created_at": {
+      "type": "string",
+      "pattern": "^\\d{4}-\\d{2}-\\d{2}[ T]\\d{2}:\\d{2}:\\d{2}(\\.\\d{1,6})?(Z|[+-]\\d{2}:\\d{2})?$"
+    },
+    "extra": {},
+    "flags": {
+      "type": [
+        "integer",
+        "null"
+      ],
+      "minimum": 0,
+      "maximum": 7
+    },
+    "id": {
+      "type": "integer",
+      "minimum": 0,
+      "maximum": 18446744073709551615
+    },
+    "is_admin": {
+      "type": "boolean"
+    },
+    "name": {
+      "description": "用户名",
+      "type": "string",
+      "maxLength": 64
+    },
+    "note": {
+      "type": [
+        "string",
+        "null"
+      ],
+      "maxLength": 65535
+    },
+    "opens_at": {
+      "type": [
+        "string",
+        "null"
+      ],
+      "pattern": "^-?\\d{2,3}:\\d{2}:\\d{2}(\\.\\d{1,6})?$"
+    },
+    "status": {
+      "type": [
+        "string",
+        "null"
+      ],
+      "enum": [
+        "active",
+        "disabled",
+        null
+      ]
+    },
+    "summary": {
+      "type": [
+        "string",
+        "null"
+      ],
+      "maxLength": 100
+    }
+  },
+  "required": [
+    "age",
+    "name",
+    "is_admin"
+  ],
+  "additionalProperties": false
+}
+`)
+	})
+}
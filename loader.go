@@ -0,0 +1,211 @@
+package sqlparser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// The naming conventions of migration files
+const (
+	FormatGolangMigrate = "golang-migrate" // 0001_create_user.up.sql and 0001_create_user.down.sql
+	FormatGoose         = "goose"          // 0001_create_user.sql with -- +goose Up and -- +goose Down
+	FormatFlyway        = "flyway"         // V1__create_user.sql, U1__create_user.sql and R__view.sql
+)
+
+// MigrationVersion is a version of the migration directory
+type MigrationVersion struct {
+	Version     string // as the file name written, e.g. 0001, 20240101120000 or 1.2, empty for repeatable
+	Description string
+	Format      string
+
+	UpFile   string
+	Up       []*Statement
+	DownFile string // empty if there is no down migration
+	Down     []*Statement
+}
+
+var (
+	golangMigrateFile = regexp.MustCompile(`^(\d+)_(.*)\.(up|down)\.sql$`)
+	gooseFile         = regexp.MustCompile(`^(\d+)_(.*)\.sql$`)
+	flywayFile        = regexp.MustCompile(`^([VU])(\d+(?:[._]\d+)*)__(.*)\.sql$`)
+	flywayRepeatable  = regexp.MustCompile(`^R__(.*)\.sql$`)
+	gooseAnnotation   = regexp.MustCompile(`(?m)^\s*--\s*\+goose\s+(Up|Down)\b.*$`)
+)
+
+// LoadMigrations read the .sql files of dir named by golang-migrate, goose or Flyway,
+// and return the versions in order. The repeatable migrations of Flyway follow
+// the versioned ones ordered by description. The statements keep the positions in the files
+func LoadMigrations(dir string) ([]*MigrationVersion, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*MigrationVersion
+	versions := map[string]*MigrationVersion{}
+	get := func(version, description, format string) *MigrationVersion {
+		key := format + "/" + version
+		if version == "" {
+			key += "/" + description
+		}
+		if mv, ok := versions[key]; ok {
+			return mv
+		}
+		mv := &MigrationVersion{Version: version, Description: description, Format: format}
+		versions[key] = mv
+		res = append(res, mv)
+		return mv
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		file := filepath.Join(dir, name)
+		var mv *MigrationVersion
+		var up bool
+		switch {
+		case golangMigrateFile.MatchString(name):
+			m := golangMigrateFile.FindStringSubmatch(name)
+			mv, up = get(m[1], m[2], FormatGolangMigrate), m[3] == "up"
+		case flywayFile.MatchString(name):
+			m := flywayFile.FindStringSubmatch(name)
+			mv, up = get(strings.ReplaceAll(m[2], "_", "."), m[3], FormatFlyway), m[1] == "V"
+		case flywayRepeatable.MatchString(name):
+			m := flywayRepeatable.FindStringSubmatch(name)
+			mv, up = get("", m[1], FormatFlyway), true
+		case gooseFile.MatchString(name):
+			m := gooseFile.FindStringSubmatch(name)
+			mv = get(m[1], m[2], FormatGoose)
+			if mv.UpFile != "" {
+				return nil, fmt.Errorf("duplicate migration version %s: %s and %s", mv.Version, mv.UpFile, file)
+			}
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return nil, err
+			}
+			upSQL, downSQL := splitGoose(string(data))
+			if mv.Up, err = parse(upSQL, file); err != nil {
+				return nil, err
+			}
+			mv.UpFile = file
+			if downSQL != "" {
+				if mv.Down, err = parse(downSQL, file); err != nil {
+					return nil, err
+				}
+				mv.DownFile = file
+			}
+			continue
+		default:
+			continue
+		}
+
+		if (up && mv.UpFile != "") || (!up && mv.DownFile != "") {
+			other := mv.UpFile
+			if !up {
+				other = mv.DownFile
+			}
+			return nil, fmt.Errorf("duplicate migration version %s: %s and %s", mv.Version, other, file)
+		}
+		stmts, err := Parse(file)
+		if err != nil {
+			return nil, err
+		}
+		if up {
+			mv.UpFile, mv.Up = file, stmts
+		} else {
+			mv.DownFile, mv.Down = file, stmts
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		a, b := res[i], res[j]
+		if (a.Version == "") != (b.Version == "") {
+			return b.Version == ""
+		}
+		if a.Version == "" {
+			return a.Description < b.Description
+		}
+		return compareVersion(a.Version, b.Version) < 0
+	})
+	return res, nil
+}
+
+// splitGoose return the up and down sections, the other section and the annotations
+// are replaced by spaces so the positions are kept. Without annotation, all is up
+func splitGoose(str string) (up, down string) {
+	matches := gooseAnnotation.FindAllStringSubmatchIndex(str, -1)
+	if len(matches) == 0 {
+		return str, ""
+	}
+	var upStr, downStr strings.Builder
+	head := blank(str[:matches[0][1]])
+	upStr.WriteString(head)
+	downStr.WriteString(head)
+	for i, m := range matches {
+		if i > 0 {
+			annotation := blank(str[m[0]:m[1]])
+			upStr.WriteString(annotation)
+			downStr.WriteString(annotation)
+		}
+		end := len(str)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		body := str[m[1]:end]
+		if str[m[2]:m[3]] == "Up" {
+			upStr.WriteString(body)
+			downStr.WriteString(blank(body))
+		} else {
+			upStr.WriteString(blank(body))
+			downStr.WriteString(body)
+		}
+	}
+	up, down = upStr.String(), downStr.String()
+	if strings.TrimSpace(down) == "" {
+		down = ""
+	}
+	return up, down
+}
+
+// blank replace every character by space except newline
+func blank(str string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\n' {
+			return r
+		}
+		return ' '
+	}, str)
+}
+
+// compareVersion compare the dotted versions numerically, e.g. 1.10 > 1.9 and 0002 > 1
+func compareVersion(a, b string) int {
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y string
+		if i < len(pa) {
+			x = strings.TrimLeft(pa[i], "0")
+		}
+		if i < len(pb) {
+			y = strings.TrimLeft(pb[i], "0")
+		}
+		if len(x) != len(y) {
+			if len(x) < len(y) {
+				return -1
+			}
+			return 1
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,105 @@
+package sqlparser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	Convey("TestLoadMigrations", t, func() {
+		dir := t.TempDir()
+		write := func(name, content string) {
+			So(os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644), ShouldBeNil)
+		}
+
+		Convey("golang-migrate", func() {
+			write("0002_add_email.up.sql", "ALTER TABLE user ADD email varchar(64);")
+			write("0002_add_email.down.sql", "ALTER TABLE user DROP email;")
+			write("0001_create_user.up.sql", "CREATE TABLE user (id int);")
+			write("0010_drop_user.up.sql", "DROP TABLE user;")
+			write("README.md", "not a migration")
+
+			res, err := LoadMigrations(dir)
+			So(err, ShouldBeNil)
+			So(len(res), ShouldEqual, 3)
+			So(res[0].Version, ShouldEqual, "0001")
+			So(res[0].Description, ShouldEqual, "create_user")
+			So(res[0].Format, ShouldEqual, FormatGolangMigrate)
+			So(res[0].Down, ShouldBeNil)
+			So(res[1].Version, ShouldEqual, "0002")
+			So(res[1].UpFile, ShouldEqual, filepath.Join(dir, "0002_add_email.up.sql"))
+			So(res[1].Up[0].Node, ShouldHaveSameTypeAs, &AlterTable{})
+			So(res[1].Down[0].File, ShouldEqual, filepath.Join(dir, "0002_add_email.down.sql"))
+			So(res[2].Version, ShouldEqual, "0010")
+		})
+
+		Convey("goose", func() {
+			write("20240102000000_add_name.sql", "-- +goose Up\n-- +goose StatementBegin\nALTER TABLE user ADD name varchar(8);\n"+
+				"-- +goose StatementEnd\n\n-- +goose Down\nALTER TABLE user DROP name;\n")
+			write("20240101000000_create_user.sql", "-- 注释\n-- +goose Up\nCREATE TABLE user (id int);\n")
+
+			res, err := LoadMigrations(dir)
+			So(err, ShouldBeNil)
+			So(len(res), ShouldEqual, 2)
+			So(res[0].Format, ShouldEqual, FormatGoose)
+			So(res[0].Description, ShouldEqual, "create_user")
+			So(res[0].Up[0].Span, ShouldResemble, Span{Start: 19, Stop: 44, Line: 3, Column: 0})
+			So(res[0].DownFile, ShouldBeEmpty)
+
+			So(len(res[1].Up), ShouldEqual, 1)
+			So(res[1].Up[0].Span.Line, ShouldEqual, 3)
+			So(len(res[1].Down), ShouldEqual, 1)
+			So(res[1].Down[0].Span.Line, ShouldEqual, 7)
+			So(res[1].Down[0].Source, ShouldEqual, "ALTER TABLE user DROP name")
+			So(res[1].DownFile, ShouldEqual, res[1].UpFile)
+		})
+
+		Convey("Flyway", func() {
+			write("V1.10__later.sql", "DROP TABLE user;")
+			write("V1_9__earlier.sql", "CREATE TABLE user (id int);\nALTER TABLE missing ADD x int;")
+			write("U1_9__earlier.sql", "DROP TABLE user;")
+			write("R__views.sql", "CREATE OR REPLACE VIEW v AS SELECT 1;")
+			write("V1__init.sql", "CREATE TABLE log (id int);")
+
+			res, err := LoadMigrations(dir)
+			So(err, ShouldBeNil)
+			var versions []string
+			for _, mv := range res {
+				versions = append(versions, mv.Version+" "+mv.Description)
+			}
+			So(versions, ShouldResemble, []string{"1 init", "1.9 earlier", "1.10 later", " views"})
+			So(res[1].DownFile, ShouldEqual, filepath.Join(dir, "U1_9__earlier.sql"))
+
+			var c Catalog
+			var applyErr error
+			for _, mv := range res {
+				for _, stmt := range mv.Up {
+					if applyErr = c.Apply(stmt); applyErr != nil {
+						break
+					}
+				}
+				if applyErr != nil {
+					break
+				}
+			}
+			So(applyErr.Error(), ShouldEqual, filepath.Join(dir, "V1_9__earlier.sql")+" line 2:0 table 'missing' doesn't exist")
+		})
+
+		Convey("Syntax error", func() {
+			write("0001_a.up.sql", "CREATE TABLE a (id int);\nALTER TABLE a ADD COLUMN y int,;\nDROP TABLE a;")
+			_, err := LoadMigrations(dir)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldStartWith, filepath.Join(dir, "0001_a.up.sql")+" line 2:31 ")
+		})
+
+		Convey("Duplicate", func() {
+			write("0001_a.up.sql", "CREATE TABLE a (id int);")
+			write("0001_b.up.sql", "CREATE TABLE b (id int);")
+			_, err := LoadMigrations(dir)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
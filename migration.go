@@ -0,0 +1,430 @@
+package sqlparser
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Migration is the statements which change the schema, generated by GenerateMigration
+type Migration struct {
+	Statements []string
+	Skipped    []*Change // the destructive changes skipped in safe mode
+}
+
+func (m *Migration) String() string {
+	str := strings.Builder{}
+	for _, stmt := range m.Statements {
+		str.WriteString(stmt)
+		str.WriteString(";\n")
+	}
+	return str.String()
+}
+
+// GenerateMigration return the statements which apply the changes returned by Diff.
+// The statements are ordered so they can be executed one by one:
+// the foreign keys are dropped first, then the tables are created, altered
+// with one ALTER TABLE per table, the foreign keys added, and the tables dropped at last.
+// In safe mode the changes losing data are skipped, i.e. dropping tables or columns
+// and changing the data type of columns
+func GenerateMigration(changes []*Change, safe bool) *Migration {
+	var res Migration
+	var tables []*TableName
+	byTable := map[string][]*Change{}
+	for _, ch := range changes {
+		if safe && destructive(ch) {
+			res.Skipped = append(res.Skipped, ch)
+			continue
+		}
+		key := ch.Table.String()
+		if _, ok := byTable[key]; !ok {
+			tables = append(tables, ch.Table)
+		}
+		byTable[key] = append(byTable[key], ch)
+	}
+
+	// drop the foreign keys before their indexes, columns and referenced tables
+	for _, name := range tables {
+		var specs []string
+		for _, ch := range byTable[name.String()] {
+			if ch.Object == ObjectForeignKey && ch.Type != ChangeAdd {
+				specs = append(specs, "DROP FOREIGN KEY "+quoteIdent(ch.Name))
+			}
+		}
+		res.add(alterTableSQL(name, specs))
+	}
+
+	// the foreign keys of new tables are added after all tables created
+	for _, name := range tables {
+		for _, ch := range byTable[name.String()] {
+			if ch.Object == ObjectTable && ch.Type == ChangeAdd {
+				res.add(createTableSQL(ch.After.(*Table), false))
+			}
+		}
+	}
+
+	for _, name := range tables {
+		res.add(alterTableSQL(name, alterSpecs(byTable[name.String()])))
+	}
+
+	// partitioning can't be combined with the other changes
+	for _, name := range tables {
+		for _, ch := range byTable[name.String()] {
+			if ch.Object != ObjectPartition {
+				continue
+			}
+			if ch.Type == ChangeDrop {
+				res.add(alterTableSQL(name, []string{"REMOVE PARTITIONING"}))
+			} else {
+				res.add(alterTableSQL(name, []string{partitionSQL(ch.After.(*Partitioning))}))
+			}
+		}
+	}
+
+	for _, name := range tables {
+		var specs []string
+		for _, ch := range byTable[name.String()] {
+			switch {
+			case ch.Object == ObjectForeignKey && ch.Type != ChangeDrop:
+				specs = append(specs, "ADD "+constraintSQL(ch.After.(*TableConstraint)))
+			case ch.Object == ObjectTable && ch.Type == ChangeAdd:
+				for _, cons := range ch.After.(*Table).Constraints {
+					if isForeignKey(cons) {
+						specs = append(specs, "ADD "+constraintSQL(cons))
+					}
+				}
+			}
+		}
+		res.add(alterTableSQL(name, specs))
+	}
+
+	for _, name := range tables {
+		for _, ch := range byTable[name.String()] {
+			if ch.Object == ObjectTable && ch.Type == ChangeDrop {
+				res.add("DROP TABLE " + tableNameSQL(name))
+			}
+		}
+	}
+	return &res
+}
+
+func (m *Migration) add(stmt string) {
+	if stmt != "" {
+		m.Statements = append(m.Statements, stmt)
+	}
+}
+
+// destructive report whether the change lose data
+func destructive(ch *Change) bool {
+	switch ch.Object {
+	case ObjectTable:
+		return ch.Type == ChangeDrop
+	case ObjectColumn:
+		if ch.Type == ChangeModify {
+			return !reflect.DeepEqual(ch.Before.(*Column).DataType, ch.After.(*Column).DataType)
+		}
+		return ch.Type == ChangeDrop
+	}
+	return false
+}
+
+// alterSpecs return the specifications of the table except foreign keys and partitioning,
+// the dropping ones first so the names can be reused
+func alterSpecs(changes []*Change) []string {
+	var drops, columns, adds []string
+	for _, ch := range changes {
+		switch ch.Object {
+		case ObjectColumn:
+			switch ch.Type {
+			case ChangeDrop:
+				drops = append(drops, "DROP COLUMN "+quoteIdent(ch.Name))
+			case ChangeAdd:
+				columns = append(columns, "ADD COLUMN "+columnSQL(ch.After.(*Column))+positionSQL(ch.AfterColumn))
+			case ChangeModify:
+				spec := "MODIFY COLUMN " + columnSQL(ch.After.(*Column))
+				if ch.Moved {
+					spec += positionSQL(ch.AfterColumn)
+				}
+				columns = append(columns, spec)
+			}
+		case ObjectIndex, ObjectCheck:
+			if ch.Type != ChangeAdd {
+				drops = append(drops, dropConstraintSQL(ch.Before.(*TableConstraint)))
+			}
+			if ch.Type != ChangeDrop {
+				adds = append(adds, "ADD "+constraintSQL(ch.After.(*TableConstraint)))
+			}
+		case ObjectOptions:
+			if options := tableOptionsSQL(ch.After.(*TableOptions)); options != "" {
+				adds = append(adds, options)
+			}
+		}
+	}
+	return append(append(drops, columns...), adds...)
+}
+
+func positionSQL(after string) string {
+	if after == "" {
+		return " FIRST"
+	}
+	return " AFTER " + quoteIdent(after)
+}
+
+func alterTableSQL(name *TableName, specs []string) string {
+	if len(specs) == 0 {
+		return ""
+	}
+	return "ALTER TABLE " + tableNameSQL(name) + "\n  " + strings.Join(specs, ",\n  ")
+}
+
+// createTableSQL return the CREATE TABLE statement, the foreign keys are omitted unless withForeignKeys
+func createTableSQL(t *Table, withForeignKeys bool) string {
+	var defs []string
+	for _, col := range t.Columns {
+		defs = append(defs, columnSQL(col))
+	}
+	for _, cons := range t.Constraints {
+		if isForeignKey(cons) && !withForeignKeys {
+			continue
+		}
+		defs = append(defs, constraintSQL(cons))
+	}
+	str := strings.Builder{}
+	str.WriteString("CREATE TABLE ")
+	str.WriteString(tableNameSQL(tableName(t)))
+	str.WriteString(" (\n  ")
+	str.WriteString(strings.Join(defs, ",\n  "))
+	str.WriteString("\n)")
+	if options := tableOptionsSQL(t.Options); options != "" {
+		str.WriteString(" ")
+		str.WriteString(options)
+	}
+	if t.Partition != nil {
+		str.WriteString("\n")
+		str.WriteString(partitionSQL(t.Partition))
+	}
+	return str.String()
+}
+
+func columnSQL(col *Column) string {
+	str := strings.Builder{}
+	str.WriteString(quoteIdent(col.Name))
+	if col.DataType != nil {
+		str.WriteString(" ")
+		str.WriteString(dataTypeSQL(col.DataType))
+	}
+	cons := col.Constraint
+	if cons == nil {
+		return str.String()
+	}
+	if cons.Generated != "" {
+		str.WriteString(" GENERATED ALWAYS AS (" + cons.Generated + ")")
+		if cons.Stored {
+			str.WriteString(" STORED")
+		} else {
+			str.WriteString(" VIRTUAL")
+		}
+	}
+	if cons.NotNull {
+		str.WriteString(" NOT NULL")
+	}
+	if cons.DefaultValue != nil {
+		str.WriteString(" DEFAULT ")
+		if cons.DefaultValue.Is {
+			str.WriteString(defaultValueSQL(cons.DefaultValue))
+		} else {
+			str.WriteString("NULL")
+		}
+	}
+	if cons.OnUpdate != "" {
+		str.WriteString(" ON UPDATE " + cons.OnUpdate)
+	}
+	if cons.AutoIncrement {
+		str.WriteString(" AUTO_INCREMENT")
+	}
+	if cons.Primary || cons.Key {
+		str.WriteString(" PRIMARY KEY")
+	}
+	if cons.Unique {
+		str.WriteString(" UNIQUE")
+	}
+	if cons.Comment != "" {
+		str.WriteString(" COMMENT ")
+		str.WriteString(quoteString(cons.Comment))
+	}
+	return str.String()
+}
+
+// dataTypeSQL return the source of the data type with the BINARY, CHARACTER SET and COLLATE
+// attributes, which are in the source already for some types like LONG VARCHAR
+func dataTypeSQL(dt *DataType) string {
+	str := dt.Source
+	upper := strings.ToUpper(str)
+	if dt.IsBinary && !strings.Contains(upper, " BINARY") {
+		str += " BINARY"
+	}
+	if dt.CharsetName != "" && !strings.Contains(upper, " SET ") {
+		str += " CHARACTER SET " + dt.CharsetName
+	}
+	if dt.CollationName != "" {
+		str += " COLLATE " + dt.CollationName
+	}
+	return str
+}
+
+// defaultValueSQL return the default by the kind of the value, the strings are quoted.
+// The value without kind is quoted unless it is a number, keyword, function or expression
+func defaultValueSQL(def *DefaultValue) string {
+	switch def.Kind {
+	case DefaultString:
+		return quoteString(def.Value)
+	case DefaultNumber, DefaultBit, DefaultHex, DefaultExpression:
+		return def.Value
+	}
+	value := def.Value
+	upper := strings.ToUpper(value)
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	switch {
+	case upper == "TRUE", upper == "FALSE",
+		strings.HasPrefix(upper, "CURRENT_TIMESTAMP"), strings.HasPrefix(upper, "NOW("),
+		strings.HasPrefix(upper, "LOCALTIME"), strings.HasPrefix(value, "("):
+		return value
+	}
+	return quoteString(value)
+}
+
+// constraintSQL return the definition used by CREATE TABLE and ADD of ALTER TABLE
+func constraintSQL(cons *TableConstraint) string {
+	switch {
+	case cons.ColumnPrimaryKey != nil:
+		return "PRIMARY KEY " + keyColumnsSQL(cons.ColumnPrimaryKey)
+	case cons.ColumnUniqueKey != nil:
+		return "UNIQUE KEY " + namedSQL(cons.Name) + keyColumnsSQL(cons.ColumnUniqueKey)
+	case cons.ColumnForeignKey != nil:
+		str := constraintNameSQL(cons.Name) + "FOREIGN KEY " + keyColumnsSQL(cons.ColumnForeignKey)
+		if ref := cons.Reference; ref != nil {
+			str += " REFERENCES " + tableNameSQL(ref.Table) + " " + keyColumnsSQL(ref.Columns)
+			if ref.OnDelete != "" {
+				str += " ON DELETE " + ref.OnDelete
+			}
+			if ref.OnUpdate != "" {
+				str += " ON UPDATE " + ref.OnUpdate
+			}
+		}
+		return str
+	case cons.Check != "":
+		return constraintNameSQL(cons.Name) + "CHECK (" + cons.Check + ")"
+	default:
+		kind := ""
+		if cons.IndexKind != "" {
+			kind = cons.IndexKind + " "
+		}
+		return kind + "KEY " + namedSQL(cons.Name) + keyColumnsSQL(cons.ColumnIndex)
+	}
+}
+
+func dropConstraintSQL(cons *TableConstraint) string {
+	switch {
+	case cons.ColumnPrimaryKey != nil:
+		return "DROP PRIMARY KEY"
+	case cons.Check != "":
+		return "DROP CHECK " + quoteIdent(cons.Name)
+	default:
+		return "DROP INDEX " + quoteIdent(cons.Name)
+	}
+}
+
+func namedSQL(name string) string {
+	if name == "" {
+		return ""
+	}
+	return quoteIdent(name) + " "
+}
+
+func constraintNameSQL(name string) string {
+	if name == "" {
+		return ""
+	}
+	return "CONSTRAINT " + quoteIdent(name) + " "
+}
+
+// keyColumnsSQL keep the functional key parts like (lower(name)) unquoted
+func keyColumnsSQL(cols []string) string {
+	var res []string
+	for _, col := range cols {
+		if strings.HasPrefix(col, "(") {
+			res = append(res, col)
+		} else {
+			res = append(res, quoteIdent(col))
+		}
+	}
+	return "(" + strings.Join(res, ", ") + ")"
+}
+
+func tableOptionsSQL(options *TableOptions) string {
+	if options == nil {
+		return ""
+	}
+	var res []string
+	if options.Engine != "" {
+		res = append(res, "ENGINE="+options.Engine)
+	}
+	if options.AutoIncrement != "" {
+		res = append(res, "AUTO_INCREMENT="+options.AutoIncrement)
+	}
+	if options.Charset != "" {
+		res = append(res, "DEFAULT CHARSET="+options.Charset)
+	}
+	if options.Collation != "" {
+		res = append(res, "COLLATE="+options.Collation)
+	}
+	if options.RowFormat != "" {
+		res = append(res, "ROW_FORMAT="+options.RowFormat)
+	}
+	if options.Comment != "" {
+		res = append(res, "COMMENT="+quoteString(options.Comment))
+	}
+	return strings.Join(res, " ")
+}
+
+func partitionSQL(part *Partitioning) string {
+	str := strings.Builder{}
+	str.WriteString("PARTITION BY ")
+	str.WriteString(part.Type)
+	switch {
+	case part.Expression != "":
+		str.WriteString(" (" + part.Expression + ")")
+	case strings.HasSuffix(part.Type, "KEY"):
+		str.WriteString(" " + keyColumnsSQL(part.Columns))
+	case part.Columns != nil:
+		str.WriteString(" COLUMNS" + keyColumnsSQL(part.Columns))
+	}
+	if part.Count > 0 {
+		str.WriteString(" PARTITIONS " + strconv.Itoa(part.Count))
+	}
+	if len(part.Partitions) > 0 {
+		var defs []string
+		for _, p := range part.Partitions {
+			defs = append(defs, p.Source)
+		}
+		str.WriteString(" (" + strings.Join(defs, ", ") + ")")
+	}
+	return str.String()
+}
+
+func tableNameSQL(name *TableName) string {
+	if name.Schema == "" {
+		return quoteIdent(name.Name)
+	}
+	return quoteIdent(name.Schema) + "." + quoteIdent(name.Name)
+}
+
+func quoteIdent(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func quoteString(str string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", "''").Replace(str) + "'"
+}
@@ -0,0 +1,115 @@
+package sqlparser
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGenerateMigration(t *testing.T) {
+	Convey("TestGenerateMigration", t, func() {
+		a, b := new(Catalog), new(Catalog)
+		So(replay(a, `CREATE TABLE org (id int PRIMARY KEY, name varchar(32), legacy int);
+CREATE TABLE user (id int PRIMARY KEY, org_id int, email varchar(64), KEY idx_org (org_id),
+  CONSTRAINT fk_org FOREIGN KEY (org_id) REFERENCES org (id));
+CREATE TABLE old_log (id int);`), ShouldBeNil)
+		So(replay(b, `CREATE TABLE org (id int PRIMARY KEY, name varchar(64) NOT NULL DEFAULT 'x' COMMENT 'org''s name');
+CREATE TABLE team (id int PRIMARY KEY, org_id int, CONSTRAINT fk_team_org FOREIGN KEY (org_id) REFERENCES org (id) ON DELETE CASCADE);
+CREATE TABLE user (id int PRIMARY KEY, team_id int, org_id int, email varchar(64), UNIQUE KEY uk_email (email),
+  KEY idx_team (team_id), CONSTRAINT fk_team FOREIGN KEY (team_id) REFERENCES team (id)) ENGINE=InnoDB;`), ShouldBeNil)
+
+		Convey("Statements", func() {
+			m := GenerateMigration(Diff(a, b), false)
+			So(m.Skipped, ShouldBeEmpty)
+			So(m.Statements, ShouldResemble, []string{
+				"ALTER TABLE `user`\n  DROP FOREIGN KEY `fk_org`",
+				"CREATE TABLE `team` (\n  `id` INT NOT NULL,\n  `org_id` INT,\n  PRIMARY KEY (`id`),\n  KEY `fk_team_org` (`org_id`)\n)",
+				"ALTER TABLE `org`\n  DROP COLUMN `legacy`,\n  MODIFY COLUMN `name` VARCHAR(64) NOT NULL DEFAULT 'x' COMMENT 'org''s name'",
+				"ALTER TABLE `user`\n  DROP INDEX `idx_org`,\n  ADD COLUMN `team_id` INT AFTER `id`,\n" +
+					"  ADD UNIQUE KEY `uk_email` (`email`),\n  ADD KEY `idx_team` (`team_id`),\n  ENGINE=InnoDB",
+				"ALTER TABLE `team`\n  ADD CONSTRAINT `fk_team_org` FOREIGN KEY (`org_id`) REFERENCES `org` (`id`) ON DELETE CASCADE",
+				"ALTER TABLE `user`\n  ADD CONSTRAINT `fk_team` FOREIGN KEY (`team_id`) REFERENCES `team` (`id`)",
+				"DROP TABLE `old_log`",
+			})
+
+			// the migration turn a into b
+			So(replay(a, m.String()), ShouldBeNil)
+			So(Diff(a, b), ShouldBeEmpty)
+		})
+
+		Convey("Safe", func() {
+			m := GenerateMigration(Diff(a, b), true)
+			var skipped [][]string
+			for _, ch := range m.Skipped {
+				skipped = append(skipped, []string{ch.Type, ch.Object, ch.Table.Name, ch.Name})
+			}
+			So(skipped, ShouldResemble, [][]string{
+				{ChangeDrop, ObjectTable, "old_log", ""},
+				{ChangeDrop, ObjectColumn, "org", "legacy"},
+				{ChangeModify, ObjectColumn, "org", "name"},
+			})
+			for _, stmt := range m.Statements {
+				So(stmt, ShouldNotContainSubstring, "DROP TABLE")
+				So(stmt, ShouldNotContainSubstring, "DROP COLUMN")
+			}
+			So(replay(a, m.String()), ShouldBeNil)
+		})
+
+		Convey("Generated", func() {
+			c, d := new(Catalog), new(Catalog)
+			So(replay(c, "CREATE TABLE t (a int, b int);"), ShouldBeNil)
+			So(replay(d, "CREATE TABLE t (a int, b int, s int GENERATED ALWAYS AS (a + b) STORED NOT NULL);"), ShouldBeNil)
+			m := GenerateMigration(Diff(c, d), false)
+			So(m.Statements, ShouldResemble, []string{
+				"ALTER TABLE `t`\n  ADD COLUMN `s` INT GENERATED ALWAYS AS (a + b) STORED NOT NULL AFTER `b`",
+			})
+			So(replay(c, m.String()), ShouldBeNil)
+			So(Diff(c, d), ShouldBeEmpty)
+		})
+
+		Convey("Charset and collation", func() {
+			c, d := new(Catalog), new(Catalog)
+			So(replay(c, "CREATE TABLE t (a varchar(8));"), ShouldBeNil)
+			So(replay(d, "CREATE TABLE t (a varchar(8) BINARY CHARACTER SET latin1, b varchar(8) COLLATE utf8mb4_bin);"), ShouldBeNil)
+			// the source is the type only
+			So(d.Table("", "t").Column("a").DataType.Source, ShouldEqual, "VARCHAR(8)")
+			m := GenerateMigration(Diff(c, d), false)
+			So(m.Statements, ShouldResemble, []string{
+				"ALTER TABLE `t`\n  MODIFY COLUMN `a` VARCHAR(8) BINARY CHARACTER SET latin1,\n" +
+					"  ADD COLUMN `b` VARCHAR(8) COLLATE utf8mb4_bin AFTER `a`",
+			})
+			So(replay(c, m.String()), ShouldBeNil)
+			So(Diff(c, d), ShouldBeEmpty)
+		})
+
+		Convey("ON UPDATE", func() {
+			c, d := new(Catalog), new(Catalog)
+			So(replay(c, "CREATE TABLE t (id int);"), ShouldBeNil)
+			So(replay(d, "CREATE TABLE t (id int, u TIMESTAMP DEFAULT NULL ON UPDATE CURRENT_TIMESTAMP, "+
+				"v TIMESTAMP(3) DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3));"), ShouldBeNil)
+			m := GenerateMigration(Diff(c, d), false)
+			So(m.Statements, ShouldResemble, []string{
+				"ALTER TABLE `t`\n  ADD COLUMN `u` TIMESTAMP DEFAULT NULL ON UPDATE CURRENT_TIMESTAMP AFTER `id`,\n" +
+					"  ADD COLUMN `v` TIMESTAMP(3) DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3) AFTER `u`",
+			})
+			So(replay(c, m.String()), ShouldBeNil)
+			So(Diff(c, d), ShouldBeEmpty)
+		})
+
+		Convey("Default literals", func() {
+			c, d := new(Catalog), new(Catalog)
+			So(replay(c, "CREATE TABLE t (id int);"), ShouldBeNil)
+			So(replay(d, "CREATE TABLE t (id int, code varchar(3) DEFAULT '007', flag bit(1) DEFAULT b'0', "+
+				"mask varbinary(2) DEFAULT 0x0A0B, n int DEFAULT -1);"), ShouldBeNil)
+			m := GenerateMigration(Diff(c, d), false)
+			So(m.Statements, ShouldResemble, []string{
+				"ALTER TABLE `t`\n  ADD COLUMN `code` VARCHAR(3) DEFAULT '007' AFTER `id`,\n" +
+					"  ADD COLUMN `flag` BIT(1) DEFAULT b'0' AFTER `code`,\n" +
+					"  ADD COLUMN `mask` VARBINARY(2) DEFAULT 0x0A0B AFTER `flag`,\n" +
+					"  ADD COLUMN `n` INT DEFAULT -1 AFTER `mask`",
+			})
+			So(replay(c, m.String()), ShouldBeNil)
+			So(Diff(c, d), ShouldBeEmpty)
+		})
+	})
+}
@@ -0,0 +1,465 @@
+package sqlparser
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// The algorithms of ALTER TABLE, from the fastest
+const (
+	AlgorithmInstant = "INSTANT" // only the metadata is changed
+	AlgorithmInplace = "INPLACE" // the table may be rebuilt without copying by the server
+	AlgorithmCopy    = "COPY"    // the rows are copied to a new table
+)
+
+// OnlineDDL is the predicted execution of an ALTER TABLE statement by InnoDB
+type OnlineDDL struct {
+	Version       string // the target MySQL version
+	Algorithm     string // the slowest algorithm of the operations
+	ConcurrentDML bool   // whether the table can be written during the statement
+	Rebuild       bool   // whether the table is rebuilt
+	Operations    []*OnlineOperation
+
+	// Error is why MySQL refuse the ALGORITHM or LOCK clause of the statement, empty if accepted
+	Error string
+}
+
+// OnlineOperation is the predicted execution of an ALTER TABLE specification
+type OnlineOperation struct {
+	Spec          *AlterSpecification
+	Algorithm     string
+	ConcurrentDML bool
+	Rebuild       bool
+	Note          string // the reason of the algorithm
+}
+
+// PredictOnlineDDL predict the algorithm of ALTER TABLE, CREATE INDEX or DROP INDEX
+// against the table in the catalog, by the target MySQL version like 5.7 or 8.0.29.
+// Return nil for the other statements or if the table doesn't exist
+func (c *Catalog) PredictOnlineDDL(stmt interface{}, version string) *OnlineDDL {
+	if s, ok := stmt.(*Statement); ok {
+		stmt = s.Node
+	}
+	var alter *AlterTable
+	switch s := stmt.(type) {
+	case *AlterTable:
+		alter = s
+	case *CreateIndex:
+		alter = &AlterTable{Table: s.Table, Specs: []*AlterSpecification{{Action: AlterAddConstraint, Constraint: s.Constraint}}}
+		alter.Specs = append(alter.Specs, indexOptionSpecs(s.Algorithm, s.Lock)...)
+	case *DropIndex:
+		alter = &AlterTable{Table: s.Table, Specs: []*AlterSpecification{{Action: AlterDropIndex, Name: s.Name}}}
+		alter.Specs = append(alter.Specs, indexOptionSpecs(s.Algorithm, s.Lock)...)
+	default:
+		return nil
+	}
+
+	origin := c.Table(alter.Table.Schema, alter.Table.Name)
+	if origin == nil {
+		return nil
+	}
+	p := &onlinePredictor{c: c, s: c.Schema(alter.Table.Schema), t: cloneTable(origin), version: version}
+	res := &OnlineDDL{Version: version, Algorithm: AlgorithmInstant, ConcurrentDML: true}
+	var algorithm, lock string
+	for _, spec := range alter.Specs {
+		switch spec.Action {
+		case AlterAlgorithm:
+			algorithm = spec.Value
+			continue
+		case AlterLock:
+			lock = spec.Value
+			continue
+		}
+		op := p.predict(spec)
+		op.Spec = spec
+		res.Operations = append(res.Operations, op)
+		if spec.Action != AlterRenameTable && alterSpecification(p.t, spec) != "" {
+			break
+		}
+	}
+
+	// DROP PRIMARY KEY is in place only with ADD PRIMARY KEY
+	for _, op := range res.Operations {
+		if op.Spec.Action == AlterDropPrimaryKey && primaryKey(p.t) != nil && p.atLeast("5.6") {
+			op.Algorithm, op.ConcurrentDML, op.Rebuild, op.Note = AlgorithmInplace, true, true, "primary key replaced"
+		}
+	}
+	for _, op := range res.Operations {
+		if algorithmOrder(op.Algorithm) > algorithmOrder(res.Algorithm) {
+			res.Algorithm = op.Algorithm
+		}
+		res.ConcurrentDML = res.ConcurrentDML && op.ConcurrentDML
+		res.Rebuild = res.Rebuild || op.Rebuild
+	}
+	if len(res.Operations) == 0 {
+		res.Algorithm = AlgorithmInplace
+	}
+	locked := lock == "SHARED" || lock == "EXCLUSIVE"
+
+	switch {
+	case algorithm == AlgorithmInstant && res.Algorithm != AlgorithmInstant,
+		algorithm == AlgorithmInplace && res.Algorithm == AlgorithmCopy:
+		res.Error = fmt.Sprintf("ALGORITHM=%s is not supported for this operation. Try ALGORITHM=%s", algorithm, res.Algorithm)
+	case algorithm == AlgorithmInstant && locked:
+		res.Error = fmt.Sprintf("LOCK=%s is not supported with ALGORITHM=INSTANT. Try LOCK=DEFAULT", lock)
+	case lock == "NONE" && !res.ConcurrentDML:
+		res.Error = "LOCK=NONE is not supported for this operation. Try LOCK=SHARED"
+	}
+	// ALGORITHM=INPLACE or a LOCK clause runs the instant operations in place
+	if res.Algorithm == AlgorithmInstant && (algorithm == AlgorithmInplace || locked) {
+		res.Algorithm = AlgorithmInplace
+	}
+	if algorithm == AlgorithmCopy {
+		res.Algorithm, res.ConcurrentDML, res.Rebuild = AlgorithmCopy, false, true
+	}
+	if locked {
+		res.ConcurrentDML = false
+	}
+	return res
+}
+
+func indexOptionSpecs(algorithm, lock string) []*AlterSpecification {
+	var res []*AlterSpecification
+	if algorithm != "" {
+		res = append(res, &AlterSpecification{Action: AlterAlgorithm, Value: algorithm})
+	}
+	if lock != "" {
+		res = append(res, &AlterSpecification{Action: AlterLock, Value: lock})
+	}
+	return res
+}
+
+func algorithmOrder(algorithm string) int {
+	switch algorithm {
+	case AlgorithmInstant:
+		return 0
+	case AlgorithmInplace:
+		return 1
+	}
+	return 2
+}
+
+// onlinePredictor predict the specifications in turn on a copy of the table
+type onlinePredictor struct {
+	c       *Catalog
+	s       *Schema
+	t       *Table
+	version string
+}
+
+func (p *onlinePredictor) atLeast(version string) bool {
+	return p.version == "" || compareVersion(p.version, version) >= 0
+}
+
+func instant(note string) *OnlineOperation {
+	return &OnlineOperation{Algorithm: AlgorithmInstant, ConcurrentDML: true, Note: note}
+}
+
+func inplace(rebuild, dml bool, note string) *OnlineOperation {
+	return &OnlineOperation{Algorithm: AlgorithmInplace, ConcurrentDML: dml, Rebuild: rebuild, Note: note}
+}
+
+func copying(note string) *OnlineOperation {
+	return &OnlineOperation{Algorithm: AlgorithmCopy, Rebuild: true, Note: note}
+}
+
+// metadata return INSTANT since 8.0, INPLACE without rebuild before
+func (p *onlinePredictor) metadata(note string) *OnlineOperation {
+	if p.atLeast("8.0") {
+		return instant(note)
+	}
+	return inplace(false, true, note)
+}
+
+func (p *onlinePredictor) predict(spec *AlterSpecification) *OnlineOperation {
+	if !p.atLeast("5.6") {
+		return copying("online DDL is supported since 5.6")
+	}
+	t := p.t
+	switch spec.Action {
+	case AlterAddColumn:
+		return p.addColumn(spec)
+	case AlterDropColumn:
+		if p.atLeast("8.0.29") && p.instantColumns() {
+			return instant("drop column")
+		}
+		return inplace(true, true, "drop column")
+	case AlterRenameColumn:
+		if p.atLeast("8.0.28") {
+			return instant("rename column")
+		}
+		return inplace(false, true, "rename column")
+	case AlterChangeColumn, AlterModifyColumn:
+		if old := t.Column(spec.Name); old != nil && spec.Column != nil {
+			return p.changeColumn(spec, old)
+		}
+	case AlterSetDefault, AlterDropDefault:
+		return p.metadata("change column default")
+	case AlterAddConstraint:
+		return p.addConstraint(spec.Constraint)
+	case AlterDropPrimaryKey:
+		return copying("drop primary key without adding one")
+	case AlterDropIndex, AlterDropConstraint, AlterDropForeignKey, AlterDropCheck:
+		return inplace(false, true, "drop index or constraint")
+	case AlterRenameIndex, AlterIndexVisibility:
+		return inplace(false, true, "change index metadata")
+	case AlterRenameTable:
+		return p.metadata("rename table")
+	case AlterConvertCharset:
+		return copying("convert character set")
+	case AlterTableOptions:
+		return p.tableOptions(spec.Options)
+	case AlterPartitionBy, AlterRemovePartition:
+		return copying("repartition")
+	case AlterAddPartition, AlterDropPartition:
+		if t.Partition != nil && (strings.HasSuffix(t.Partition.Type, "HASH") || strings.HasSuffix(t.Partition.Type, "KEY")) {
+			return inplace(false, false, "redistribute rows of hash partitions")
+		}
+		return inplace(false, true, "add or drop range or list partition")
+	}
+	return copying("unknown operation")
+}
+
+// instantColumns report whether the columns of table can be added or dropped instantly
+func (p *onlinePredictor) instantColumns() bool {
+	if p.t.Options != nil && strings.EqualFold(p.t.Options.RowFormat, "COMPRESSED") {
+		return false
+	}
+	for _, cons := range p.t.Constraints {
+		if cons.IndexKind == "FULLTEXT" {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *onlinePredictor) addColumn(spec *AlterSpecification) *OnlineOperation {
+	col := spec.Column
+	var res *OnlineOperation
+	last := !spec.First && (spec.After == "" || len(p.t.Columns) != 0 && strings.EqualFold(p.t.Columns[len(p.t.Columns)-1].Name, spec.After))
+	switch {
+	case col.Constraint != nil && col.Constraint.AutoIncrement:
+		res = inplace(true, false, "add auto-increment column")
+	case p.atLeast("8.0.29") && p.instantColumns():
+		res = instant("add column")
+	case p.atLeast("8.0.12") && p.instantColumns() && last:
+		res = instant("add column last")
+	default:
+		res = inplace(true, true, "add column")
+	}
+	if col.Constraint != nil && (col.Constraint.Primary || col.Constraint.Key) {
+		res = worse(res, inplace(true, true, "add primary key"))
+	} else if col.Constraint != nil && col.Constraint.Unique {
+		res = worse(res, inplace(false, true, "add index"))
+	}
+	return res
+}
+
+func (p *onlinePredictor) changeColumn(spec *AlterSpecification, old *Column) *OnlineOperation {
+	col := spec.Column
+	res := p.metadata("change column metadata")
+	if !strings.EqualFold(old.Name, col.Name) {
+		if p.atLeast("8.0.28") {
+			res = instant("rename column")
+		} else {
+			res = inplace(false, true, "rename column")
+		}
+	}
+
+	a, b := old.DataType, col.DataType
+	if a != nil && b != nil && !reflect.DeepEqual(typeIdentity(a), typeIdentity(b)) {
+		res = worse(res, p.changeType(old, col))
+	}
+	before, after := columnCharset(p.s, p.t, old), columnCharset(p.s, p.t, col)
+	if !strings.EqualFold(normalizeCharset(before.charset), normalizeCharset(after.charset)) ||
+		!strings.EqualFold(normalizeCollation(before.collation), normalizeCollation(after.collation)) {
+		res = worse(res, copying("change column charset or collation"))
+	}
+
+	oa, ob := old.Constraint, col.Constraint
+	if oa == nil {
+		oa = new(ColumnConstraint)
+	}
+	if ob == nil {
+		ob = new(ColumnConstraint)
+	}
+	if pk := primaryKey(p.t); pk != nil && containsName(pk.ColumnPrimaryKey, old.Name) {
+		// the primary key columns are NOT NULL implicitly
+		copied := *ob
+		copied.NotNull = true
+		ob = &copied
+	}
+	if oa.NotNull != ob.NotNull {
+		res = worse(res, inplace(true, true, "change column nullability"))
+	}
+	if oa.AutoIncrement != ob.AutoIncrement {
+		res = worse(res, copying("change column auto-increment"))
+	}
+	if spec.First || spec.After != "" {
+		res = worse(res, inplace(true, true, "reorder column"))
+	}
+	if ob.Primary || ob.Key {
+		res = worse(res, inplace(true, true, "add primary key"))
+	} else if ob.Unique {
+		res = worse(res, inplace(false, true, "add index"))
+	}
+	return res
+}
+
+// typeIdentity return the data type without source text and charset, which are compared separately.
+// The aliases and the default lengths are normalized, e.g. INTEGER is INT, DECIMAL is DECIMAL(10,0)
+// and CHAR is CHAR(1), the display width of the integer types is ignored like MySQL 8.0
+func typeIdentity(dt *DataType) DataType {
+	res := DataType{
+		Name:              foreignKeyType(dt),
+		HasLength:         dt.HasLength && dt.Length != 0,
+		Length:            dt.Length,
+		IsUnsigned:        dt.IsUnsigned || dt.IsZeroFill,
+		IsZeroFill:        dt.IsZeroFill,
+		HasTwoLength:      dt.HasTwoLength,
+		Len1:              dt.Len1,
+		Len2:              dt.Len2,
+		IsVarchar:         dt.IsVarchar,
+		CollectionOptions: dt.CollectionOptions,
+	}
+	// foreignKeyType treat the VARCHAR as CHAR and the VARBINARY as BINARY
+	switch {
+	case dt.Name == "VARCHAR" || dt.Name == "NVARCHAR" || dt.Name == "VARCHARACTER" || dt.IsVarying:
+		res.Name = "VARCHAR"
+	case dt.Name == "VARBINARY":
+		res.Name = "VARBINARY"
+	}
+	switch {
+	case isIntegerType(res.Name) || res.Name == "YEAR":
+		res.HasLength, res.Length = false, 0
+	case res.Name == "DECIMAL":
+		res.HasLength, res.Length, res.HasTwoLength = false, 0, true
+		res.Len1, res.Len2 = decimalPrecision(dt)
+	case res.Name == "CHAR" || res.Name == "BINARY" || res.Name == "BIT":
+		if !res.HasLength {
+			res.HasLength, res.Length = true, 1
+		}
+	}
+	if !res.HasLength {
+		res.Length = 0
+	}
+	return res
+}
+
+// changeType predict the changed data type, only extending VARCHAR within the
+// length bytes and appending ENUM or SET values are done without copy
+func (p *onlinePredictor) changeType(old, col *Column) *OnlineOperation {
+	a, b := old.DataType, col.DataType
+	ta, tb := foreignKeyType(a), foreignKeyType(b)
+	switch {
+	case a.Name == "VARCHAR" && b.Name == "VARCHAR" && b.Length >= a.Length:
+		size := charsetMaxBytes(columnCharset(p.s, p.t, old).charset, p.version)
+		if (a.Length*size <= 255) != (b.Length*size <= 255) {
+			return copying("extend VARCHAR across 255 bytes")
+		}
+		return inplace(false, true, "extend VARCHAR")
+	case (ta == "ENUM" || ta == "SET") && ta == tb && len(b.CollectionOptions) > len(a.CollectionOptions) &&
+		reflect.DeepEqual(a.CollectionOptions, b.CollectionOptions[:len(a.CollectionOptions)]) &&
+		collectionBytes(ta, len(a.CollectionOptions)) == collectionBytes(ta, len(b.CollectionOptions)):
+		return p.metadata("append " + ta + " values")
+	}
+	return copying("change column type")
+}
+
+// collectionBytes return the storage size of ENUM or SET of n values
+func collectionBytes(name string, n int) int {
+	if name == "ENUM" {
+		if n < 256 {
+			return 1
+		}
+		return 2
+	}
+	return (n + 7) / 8
+}
+
+// charsetMaxBytes return the max bytes of a character, the unknown charset is the default
+// of the version, latin1 before 8.0 and utf8mb4 since
+func charsetMaxBytes(charset, version string) int {
+	switch normalizeCharset(charset) {
+	case "":
+		if version != "" && compareVersion(version, "8.0") < 0 {
+			return 1
+		}
+		return 4
+	case "latin1", "latin2", "latin5", "latin7", "ascii", "binary", "cp1250", "cp1251", "cp1256", "cp1257",
+		"cp850", "cp852", "cp866", "dec8", "greek", "hebrew", "hp8", "keybcs2", "koi8r", "koi8u", "macce", "macroman",
+		"swe7", "tis620", "armscii8", "geostd8":
+		return 1
+	case "ucs2", "gbk", "big5", "sjis", "cp932", "euckr", "gb2312":
+		return 2
+	case "utf8mb3", "ujis", "eucjpms":
+		return 3
+	}
+	return 4
+}
+
+func (p *onlinePredictor) addConstraint(cons *TableConstraint) *OnlineOperation {
+	switch {
+	case cons.ColumnPrimaryKey != nil:
+		for _, name := range cons.ColumnPrimaryKey {
+			if col := p.t.Column(name); col != nil && (col.Constraint == nil || !col.Constraint.NotNull) {
+				return copying("add primary key on nullable columns")
+			}
+		}
+		return inplace(true, true, "add primary key")
+	case isForeignKey(cons):
+		return copying("add foreign key, in place only if foreign_key_checks is disabled")
+	case isCheck(cons):
+		return copying("add check constraint")
+	case cons.IndexKind == "FULLTEXT":
+		for _, other := range p.t.Constraints {
+			if other.IndexKind == "FULLTEXT" {
+				return inplace(false, false, "add fulltext index")
+			}
+		}
+		return inplace(true, false, "add first fulltext index")
+	case cons.IndexKind == "SPATIAL":
+		return inplace(false, false, "add spatial index")
+	}
+	return inplace(false, true, "add index")
+}
+
+func (p *onlinePredictor) tableOptions(options *TableOptions) *OnlineOperation {
+	if options == nil {
+		return inplace(false, true, "change table options")
+	}
+	var current TableOptions
+	if p.t.Options != nil {
+		current = *p.t.Options
+	}
+	res := p.metadata("change table options")
+	if options.Engine != "" {
+		if !strings.EqualFold(options.Engine, "InnoDB") || current.Engine != "" && !strings.EqualFold(current.Engine, "InnoDB") {
+			return copying("change storage engine")
+		}
+		res = worse(res, inplace(true, true, "rebuild table"))
+	}
+	if options.RowFormat != "" {
+		res = worse(res, inplace(true, true, "change row format"))
+	}
+	if options.Charset != "" || options.Collation != "" {
+		// in-place with a rebuild, but the concurrent DML is not permitted
+		res = worse(res, inplace(true, false, "change default character set"))
+	}
+	if options.AutoIncrement != "" || options.Comment != "" {
+		res = worse(res, inplace(false, true, "change table metadata"))
+	}
+	return res
+}
+
+// worse return the slower operation, the rebuild and lock are merged
+func worse(a, b *OnlineOperation) *OnlineOperation {
+	res := *a
+	if algorithmOrder(b.Algorithm) > algorithmOrder(a.Algorithm) {
+		res.Algorithm, res.Note = b.Algorithm, b.Note
+	}
+	res.ConcurrentDML = a.ConcurrentDML && b.ConcurrentDML
+	res.Rebuild = a.Rebuild || b.Rebuild
+	return &res
+}
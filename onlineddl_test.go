@@ -0,0 +1,117 @@
+package sqlparser
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPredictOnlineDDL(t *testing.T) {
+	Convey("TestPredictOnlineDDL", t, func() {
+		c := new(Catalog)
+		So(replay(c, `CREATE TABLE user (id int PRIMARY KEY, name varchar(60), code varchar(60) CHARACTER SET latin1,
+  role enum('a','b'), age int, KEY idx_age (age)) DEFAULT CHARSET=utf8mb4;`), ShouldBeNil)
+
+		predict := func(str, version string) *OnlineDDL {
+			stmts, err := Parse(str)
+			So(err, ShouldBeNil)
+			return c.PredictOnlineDDL(stmts[0], version)
+		}
+		algorithms := func(res *OnlineDDL) []string {
+			var algs []string
+			for _, op := range res.Operations {
+				algs = append(algs, op.Algorithm)
+			}
+			return algs
+		}
+
+		Convey("AddColumn", func() {
+			res := predict("ALTER TABLE user ADD COLUMN email varchar(64)", "8.0.12")
+			So(res.Algorithm, ShouldEqual, AlgorithmInstant)
+			So(res.ConcurrentDML, ShouldBeTrue)
+			So(res.Rebuild, ShouldBeFalse)
+
+			So(predict("ALTER TABLE user ADD COLUMN email varchar(64) AFTER id", "8.0.12").Algorithm, ShouldEqual, AlgorithmInplace)
+			So(predict("ALTER TABLE user ADD COLUMN email varchar(64) AFTER id", "8.0.29").Algorithm, ShouldEqual, AlgorithmInstant)
+
+			res = predict("ALTER TABLE user ADD COLUMN email varchar(64)", "5.7")
+			So(res.Algorithm, ShouldEqual, AlgorithmInplace)
+			So(res.Rebuild, ShouldBeTrue)
+			So(res.ConcurrentDML, ShouldBeTrue)
+
+			So(predict("ALTER TABLE user ADD COLUMN email varchar(64)", "5.5").Algorithm, ShouldEqual, AlgorithmCopy)
+
+			// ON UPDATE CURRENT_TIMESTAMP is not AUTO_INCREMENT
+			res = predict("ALTER TABLE user ADD COLUMN u timestamp NULL ON UPDATE CURRENT_TIMESTAMP", "8.0.30")
+			So(res.Algorithm, ShouldEqual, AlgorithmInstant)
+			So(res.Operations[0].Note, ShouldEqual, "add column")
+			So(predict("ALTER TABLE user ADD COLUMN id2 int AUTO_INCREMENT UNIQUE", "8.0.30").Algorithm, ShouldEqual, AlgorithmInplace)
+		})
+
+		Convey("ChangeColumn", func() {
+			res := predict("ALTER TABLE user MODIFY age bigint", "8.0.30")
+			So(res.Algorithm, ShouldEqual, AlgorithmCopy)
+			So(res.ConcurrentDML, ShouldBeFalse)
+
+			// utf8mb4 is 4 bytes a character, 60 * 4 < 256 and 64 * 4 > 255
+			So(predict("ALTER TABLE user MODIFY name varchar(63)", "8.0").Algorithm, ShouldEqual, AlgorithmInplace)
+			So(predict("ALTER TABLE user MODIFY name varchar(64)", "8.0").Algorithm, ShouldEqual, AlgorithmCopy)
+			So(predict("ALTER TABLE user MODIFY code varchar(255) CHARACTER SET latin1", "8.0").Algorithm, ShouldEqual, AlgorithmInplace)
+			So(predict("ALTER TABLE user MODIFY name varchar(30)", "8.0").Algorithm, ShouldEqual, AlgorithmCopy)
+			So(predict("ALTER TABLE user MODIFY code varchar(60)", "8.0").Algorithm, ShouldEqual, AlgorithmCopy)
+
+			So(predict("ALTER TABLE user MODIFY role enum('a','b','c')", "8.0").Algorithm, ShouldEqual, AlgorithmInstant)
+			So(predict("ALTER TABLE user MODIFY role enum('b','a','c')", "8.0").Algorithm, ShouldEqual, AlgorithmCopy)
+
+			So(predict("ALTER TABLE user MODIFY id int COMMENT 'key'", "8.0").Algorithm, ShouldEqual, AlgorithmInstant)
+			So(predict("ALTER TABLE user ALTER age SET DEFAULT 1", "5.7").Algorithm, ShouldEqual, AlgorithmInplace)
+			So(algorithms(predict("ALTER TABLE user RENAME COLUMN age TO years, MODIFY name varchar(60) NOT NULL", "8.0.28")),
+				ShouldResemble, []string{AlgorithmInstant, AlgorithmInplace})
+			So(predict("ALTER TABLE user CHANGE age years int", "8.0").Rebuild, ShouldBeFalse)
+		})
+
+		Convey("Index", func() {
+			res := predict("CREATE INDEX idx_name ON user (name) ALGORITHM=INPLACE LOCK=NONE", "5.7")
+			So(res.Algorithm, ShouldEqual, AlgorithmInplace)
+			So(res.ConcurrentDML, ShouldBeTrue)
+			So(res.Error, ShouldBeEmpty)
+
+			res = predict("ALTER TABLE user ADD FULLTEXT INDEX ft_name (name), LOCK=NONE", "8.0")
+			So(res.Rebuild, ShouldBeTrue)
+			So(res.Error, ShouldEqual, "LOCK=NONE is not supported for this operation. Try LOCK=SHARED")
+
+			So(predict("ALTER TABLE user DROP PRIMARY KEY", "8.0").Algorithm, ShouldEqual, AlgorithmCopy)
+			So(predict("ALTER TABLE user DROP PRIMARY KEY, ADD PRIMARY KEY (id, age)", "8.0").Algorithm, ShouldEqual, AlgorithmCopy)
+			So(predict("ALTER TABLE user MODIFY age int NOT NULL, DROP PRIMARY KEY, ADD PRIMARY KEY (id, age)", "8.0").Algorithm,
+				ShouldEqual, AlgorithmInplace)
+			So(predict("ALTER TABLE user ADD CONSTRAINT fk FOREIGN KEY (age) REFERENCES user (id)", "8.0").Algorithm, ShouldEqual, AlgorithmCopy)
+			So(predict("DROP INDEX idx_age ON user", "8.0").Algorithm, ShouldEqual, AlgorithmInplace)
+		})
+
+		Convey("Algorithm", func() {
+			res := predict("ALTER TABLE user MODIFY age bigint, ALGORITHM=INPLACE", "8.0")
+			So(res.Error, ShouldEqual, "ALGORITHM=INPLACE is not supported for this operation. Try ALGORITHM=COPY")
+
+			res = predict("ALTER TABLE user ADD COLUMN x int, ALGORITHM=INSTANT", "8.0.11")
+			So(res.Error, ShouldEqual, "ALGORITHM=INSTANT is not supported for this operation. Try ALGORITHM=INPLACE")
+
+			res = predict("ALTER TABLE user ADD COLUMN x int, ALGORITHM=COPY", "8.0.30")
+			So(res.Algorithm, ShouldEqual, AlgorithmCopy)
+			So(res.ConcurrentDML, ShouldBeFalse)
+
+			res = predict("ALTER TABLE user ADD COLUMN x int, LOCK=SHARED", "8.0.30")
+			So(res.Algorithm, ShouldEqual, AlgorithmInplace)
+			So(res.ConcurrentDML, ShouldBeFalse)
+
+			So(predict("ALTER TABLE user ENGINE=MyISAM", "8.0").Algorithm, ShouldEqual, AlgorithmCopy)
+			So(predict("ALTER TABLE user CONVERT TO CHARACTER SET latin1", "8.0").Algorithm, ShouldEqual, AlgorithmCopy)
+			res = predict("ALTER TABLE user CHARACTER SET latin1", "8.0")
+			So(res.Algorithm, ShouldEqual, AlgorithmInplace)
+			So(res.Rebuild, ShouldBeTrue)
+			So(res.ConcurrentDML, ShouldBeFalse)
+			So(predict("ALTER TABLE user COLLATE utf8mb4_bin", "8.0").ConcurrentDML, ShouldBeFalse)
+			So(predict("ALTER TABLE user RENAME TO person", "8.0").Algorithm, ShouldEqual, AlgorithmInstant)
+			So(predict("ALTER TABLE missing ADD x int", "8.0"), ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,174 @@
+package sqlparser
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OpenAPISchemas return the OpenAPI 3.1 component schemas of the tables. For a table like user
+// the schemas are:
+//   - User, the row, the AUTO_INCREMENT and generated columns are readOnly
+//   - UserCreate, the body to create, without the columns managed by the server
+//   - UserUpdate, the body to update, without the managed columns and nothing required
+//
+// The managed columns are the AUTO_INCREMENT and generated columns, and the DATE, DATETIME and
+// TIMESTAMP columns of DEFAULT CURRENT_TIMESTAMP or ON UPDATE CURRENT_TIMESTAMP like create_time and
+// update_time. The defaults are typed by the column, the function and expression defaults are omitted
+func OpenAPISchemas(tables []*Table) map[string]*JSONSchema {
+	res := map[string]*JSONSchema{}
+	for _, t := range tables {
+		name := pascalCase(t.Name, false)
+		additional := false
+		row := &JSONSchema{Title: t.Name, Description: tableComment(t), Type: "object", Properties: map[string]*JSONSchema{}}
+		create := &JSONSchema{Type: "object", Properties: map[string]*JSONSchema{}, AdditionalProperties: &additional}
+		update := &JSONSchema{Type: "object", Properties: map[string]*JSONSchema{}, AdditionalProperties: &additional}
+		for _, col := range t.Columns {
+			s := columnJSONSchema(t, col)
+			if col.Constraint != nil && col.Constraint.DefaultValue != nil && col.Constraint.DefaultValue.Is {
+				s.Default = typedDefault(col.DataType, col.Constraint.DefaultValue.Value)
+			}
+			if col.Constraint != nil && (col.Constraint.AutoIncrement || col.Constraint.Generated != "") {
+				s.ReadOnly = true
+			}
+			row.Properties[col.Name] = s
+			if columnRequired(t, col) {
+				row.Required = append(row.Required, col.Name)
+			}
+			if serverManaged(col) {
+				continue
+			}
+			create.Properties[col.Name] = s
+			update.Properties[col.Name] = s
+			if columnRequired(t, col) {
+				create.Required = append(create.Required, col.Name)
+			}
+		}
+		res[name] = row
+		res[name+"Create"] = create
+		res[name+"Update"] = update
+	}
+	return res
+}
+
+// GenerateOpenAPIJSON return the indented JSON of the components of OpenAPISchemas
+func GenerateOpenAPIJSON(tables []*Table) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	err := enc.Encode(map[string]interface{}{"components": map[string]interface{}{"schemas": OpenAPISchemas(tables)}})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GenerateOpenAPIYAML return the YAML of the components of OpenAPISchemas
+func GenerateOpenAPIYAML(tables []*Table) ([]byte, error) {
+	data, err := GenerateOpenAPIJSON(tables)
+	if err != nil {
+		return nil, err
+	}
+	// decoded from the JSON to keep the numbers and the order of the keywords
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	plainStyle(&node)
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// plainStyle clear the flow and quoted styles of the nodes decoded from JSON,
+// the strings like "true" or "1" are still quoted by the encoder
+func plainStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		plainStyle(child)
+	}
+}
+
+// serverManaged report whether the column is set by the server, not by the request body
+func serverManaged(col *Column) bool {
+	cons := col.Constraint
+	if cons == nil {
+		return false
+	}
+	if cons.AutoIncrement || cons.Generated != "" || cons.OnUpdate != "" {
+		return true
+	}
+	if cons.DefaultValue == nil || !cons.DefaultValue.Is || col.DataType == nil {
+		return false
+	}
+	switch foreignKeyType(col.DataType) {
+	case "DATE", "DATETIME", "TIMESTAMP":
+		return isTimestampFunction(cons.DefaultValue.Value)
+	}
+	return false
+}
+
+func isTimestampFunction(value string) bool {
+	upper := strings.ToUpper(value)
+	for _, prefix := range []string{"CURRENT_TIMESTAMP", "NOW(", "LOCALTIME", "CURRENT_DATE", "CURDATE("} {
+		if strings.HasPrefix(upper, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// typedDefault return the default in the JSON type of the column,
+// nil for the functions and expressions
+func typedDefault(dt *DataType, value string) interface{} {
+	if isTimestampFunction(value) || strings.HasPrefix(value, "(") {
+		return nil
+	}
+	if dt == nil {
+		return value
+	}
+	s := dataTypeJSONSchema(dt)
+	switch s.Type {
+	case "boolean":
+		switch strings.ToUpper(value) {
+		case "1", "TRUE":
+			return true
+		case "0", "FALSE":
+			return false
+		}
+		return nil
+	case "integer":
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return json.Number(strconv.FormatInt(n, 10))
+		}
+		if n, err := strconv.ParseUint(value, 10, 64); err == nil {
+			return json.Number(strconv.FormatUint(n, 10))
+		}
+		return nil
+	case "number":
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil
+		}
+		// DECIMAL like 1.50 is kept, +1 or .5 is not a JSON number
+		if !json.Valid([]byte(value)) {
+			return json.Number(strconv.FormatFloat(f, 'f', -1, 64))
+		}
+		return json.Number(value)
+	case nil:
+		// JSON
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,103 @@
+package sqlparser
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOpenAPISchemas(t *testing.T) {
+	Convey("TestOpenAPISchemas", t, func() {
+		tables, err := From(`CREATE TABLE user_account (
+  id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  name varchar(64) NOT NULL COMMENT '用户名',
+  status enum('active','disabled') NOT NULL DEFAULT 'active',
+  score int NOT NULL DEFAULT '007',
+  ratio decimal(3,2) DEFAULT 1.50,
+  is_admin bool NOT NULL DEFAULT 0,
+  full_name varchar(130) GENERATED ALWAYS AS (concat(name, ' ', status)) VIRTUAL,
+  create_time datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) COMMENT 'users';`)
+		So(err, ShouldBeNil)
+
+		Convey("Schemas", func() {
+			schemas := OpenAPISchemas(tables)
+			So(len(schemas), ShouldEqual, 3)
+			row := schemas["UserAccount"]
+			So(row.Properties["id"].ReadOnly, ShouldBeTrue)
+			So(row.Properties["full_name"].ReadOnly, ShouldBeTrue)
+			So(row.Properties["create_time"].ReadOnly, ShouldBeFalse)
+			So(row.Properties["create_time"].Default, ShouldBeNil)
+			So(row.Required, ShouldResemble, []string{"name"})
+
+			create := schemas["UserAccountCreate"]
+			So(len(create.Properties), ShouldEqual, 5)
+			So(create.Properties["id"], ShouldBeNil)
+			So(create.Properties["full_name"], ShouldBeNil)
+			So(create.Properties["update_time"], ShouldBeNil)
+			So(create.Required, ShouldResemble, []string{"name"})
+			So(len(schemas["UserAccountUpdate"].Properties), ShouldEqual, 5)
+			So(schemas["UserAccountUpdate"].Required, ShouldBeNil)
+		})
+
+		Convey("YAML", func() {
+			res, err := GenerateOpenAPIYAML(tables)
+			So(err, ShouldBeNil)
+			So(string(res), ShouldStartWith, `components:
+  schemas:
+    UserAccount:
+      title: user_account
+      description: users
+      type: object
+      properties:
+        create_time:
+          type: string
+          pattern: ^\d{4}-\d{2}-\d{2}[ T]\d{2}:\d{2}:\d{2}(\.\d{1,6})?(Z|[+-]\d{2}:\d{2})?$
+        full_name:
+          type:
+            - string
+            - "null"
+          maxLength: 130
+          readOnly: true
+        id:
+          type: integer
+          minimum: 0
+          maximum: 18446744073709551615
+          readOnly: true
+        is_admin:
+          type: boolean
+          default: false
+        name:
+          description: 用户名
+          type: string
+          maxLength: 64
+        ratio:
+          type:
+            - number
+            - "null"
+          minimum: -9.99
+          maximum: 9.99
+          default: 1.50
+        score:
+          type: integer
+          minimum: -2147483648
+          maximum: 2147483647
+          default: 7
+        status:
+          type: string
+          enum:
+            - active
+            - disabled
+          default: active
+`)
+			So(string(res), ShouldContainSubstring, "    UserAccountCreate:\n      type: object\n")
+		})
+
+		Convey("JSON", func() {
+			res, err := GenerateOpenAPIJSON(tables)
+			So(err, ShouldBeNil)
+			So(string(res), ShouldContainSubstring, `"default": 1.50`)
+		})
+	})
+}
@@ -0,0 +1,312 @@
+package sqlparser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ProtoOptions is the options of GenerateProto, the zero value is usable
+type ProtoOptions struct {
+	Package   string // the proto package, model if empty
+	GoPackage string // the go_package option, omitted if empty
+
+	// Lock is the numbers of the previous generation, nil for the first one
+	Lock *ProtoLock
+}
+
+// ProtoLock is the field and enum value numbers of the generated messages, kept across the
+// generations so a column keeps its number. The names are in lower case
+type ProtoLock struct {
+	Messages map[string]*ProtoNumbers `json:"messages,omitempty"` // by table name
+	Enums    map[string]*ProtoNumbers `json:"enums,omitempty"`    // by table and column name like user.status
+}
+
+// ProtoNumbers is the numbers by name, the numbers of the removed names are reserved and never
+// reused. A name added back gets its reserved number again
+type ProtoNumbers struct {
+	Numbers  map[string]int `json:"numbers,omitempty"`
+	Reserved map[string]int `json:"reserved,omitempty"`
+}
+
+// MarshalProtoLock return the indented JSON of the lock file, ending with a newline
+func MarshalProtoLock(l *ProtoLock) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(l); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalProtoLock return the lock of the JSON lock file
+func UnmarshalProtoLock(data []byte) (*ProtoLock, error) {
+	var l ProtoLock
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&l); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// GenerateProto generate the proto3 file of one message per table, and return the lock of the
+// numbers to save for the next generation. The nullable columns are the wrapper types, the
+// DATE, DATETIME and TIMESTAMP columns are google.protobuf.Timestamp, and the ENUM columns are
+// nested enums. The tables of lock not in tables are dropped from the new lock
+func GenerateProto(tables []*Table, opts *ProtoOptions) ([]byte, *ProtoLock, error) {
+	var o ProtoOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Package == "" {
+		o.Package = "model"
+	}
+	old := o.Lock
+	if old == nil {
+		old = &ProtoLock{}
+	}
+	lock := &ProtoLock{Messages: map[string]*ProtoNumbers{}, Enums: map[string]*ProtoNumbers{}}
+	imports := map[string]bool{}
+
+	var body strings.Builder
+	for _, t := range tables {
+		if err := writeMessage(&body, t, old, lock, imports); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var str strings.Builder
+	str.WriteString("// Code generated by sqlparser. DO NOT EDIT.\n\n")
+	str.WriteString("syntax = \"proto3\";\n\n")
+	str.WriteString("package " + o.Package + ";\n\n")
+	if len(imports) != 0 {
+		var paths []string
+		for path := range imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			str.WriteString(fmt.Sprintf("import %q;\n", path))
+		}
+		str.WriteString("\n")
+	}
+	if o.GoPackage != "" {
+		str.WriteString(fmt.Sprintf("option go_package = %q;\n\n", o.GoPackage))
+	}
+	str.WriteString(strings.TrimSuffix(body.String(), "\n"))
+	if len(lock.Enums) == 0 {
+		lock.Enums = nil
+	}
+	return []byte(str.String()), lock, nil
+}
+
+func writeMessage(str *strings.Builder, t *Table, old, lock *ProtoLock, imports map[string]bool) error {
+	name := pascalCase(t.Name, false)
+	key := strings.ToLower(t.Name)
+	var names []string
+	fields := map[string]string{}
+	for _, col := range t.Columns {
+		field := snakeCase(col.Name)
+		if other, ok := fields[field]; ok {
+			return fmt.Errorf("table '%s': columns '%s' and '%s' are both the proto field %s", t.Name, other, col.Name, field)
+		}
+		fields[field] = col.Name
+		names = append(names, strings.ToLower(col.Name))
+	}
+	numbers := old.Messages[key].assign(names, 1)
+	lock.Messages[key] = numbers
+
+	writeComment(str, "", "", tableComment(t))
+	str.WriteString("message " + name + " {\n")
+	writeReserved(str, "  ", numbers, func(name string) string { return snakeCase(name) })
+
+	var enums strings.Builder
+	for _, col := range t.Columns {
+		if col.Constraint != nil && col.Constraint.Comment != "" {
+			writeComment(str, "  ", "", col.Constraint.Comment)
+		}
+		field := snakeCase(col.Name)
+		number := numbers.Numbers[strings.ToLower(col.Name)]
+		if col.DataType != nil && col.DataType.Name == "ENUM" {
+			typ := pascalCase(col.Name, false)
+			enumKey := key + "." + strings.ToLower(col.Name)
+			values := old.Enums[enumKey].assign(col.DataType.CollectionOptions, 1)
+			lock.Enums[enumKey] = values
+			writeEnum(&enums, typ, col.DataType.CollectionOptions, values)
+			if columnNullable(t, col) {
+				typ = "optional " + typ
+			}
+			str.WriteString(fmt.Sprintf("  %s %s = %d;\n", typ, field, number))
+			continue
+		}
+		typ := protoType(col.DataType, columnNullable(t, col))
+		switch {
+		case typ == "google.protobuf.Timestamp":
+			imports["google/protobuf/timestamp.proto"] = true
+		case strings.HasPrefix(typ, "google.protobuf."):
+			imports["google/protobuf/wrappers.proto"] = true
+		}
+		str.WriteString(fmt.Sprintf("  %s %s = %d;\n", typ, field, number))
+	}
+	if enums.Len() != 0 {
+		str.WriteString("\n")
+		str.WriteString(strings.TrimSuffix(enums.String(), "\n"))
+	}
+	str.WriteString("}\n\n")
+	return nil
+}
+
+// writeEnum write the nested enum, the zero value is the UNSPECIFIED one
+func writeEnum(str *strings.Builder, name string, options []string, values *ProtoNumbers) {
+	prefix := strings.ToUpper(snakeCase(name))
+	idents := enumValueNames(prefix, values)
+	str.WriteString("  enum " + name + " {\n")
+	writeReserved(str, "    ", values, func(value string) string { return idents[value] })
+	str.WriteString(fmt.Sprintf("    %s_UNSPECIFIED = 0;\n", prefix))
+	for _, option := range options {
+		str.WriteString(fmt.Sprintf("    %s = %d; // %q\n", idents[option], values.Numbers[option], option))
+	}
+	str.WriteString("  }\n\n")
+}
+
+var protoIdentifier = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// enumValueNames return the names of the ENUM values prefixed by the enum name, in the order of
+// numbers so the names are stable. The value not in letters or digits, or named as another one,
+// is named by its number
+func enumValueNames(prefix string, values *ProtoNumbers) map[string]string {
+	numbers := map[string]int{}
+	var all []string
+	for _, m := range []map[string]int{values.Numbers, values.Reserved} {
+		for value, number := range m {
+			numbers[value] = number
+			all = append(all, value)
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return numbers[all[i]] < numbers[all[j]] })
+
+	res := map[string]string{}
+	used := map[string]bool{prefix + "_UNSPECIFIED": true}
+	for _, value := range all {
+		name := prefix + "_" + strings.ToUpper(snakeCase(value))
+		if value == "" {
+			name = prefix + "_EMPTY"
+		}
+		if !protoIdentifier.MatchString(name) || used[name] {
+			name = fmt.Sprintf("%s_VALUE_%d", prefix, numbers[value])
+		}
+		used[name] = true
+		res[value] = name
+	}
+	return res
+}
+
+// writeReserved write the reserved numbers and names in the order of numbers
+func writeReserved(str *strings.Builder, indent string, numbers *ProtoNumbers, ident func(name string) string) {
+	if len(numbers.Reserved) == 0 {
+		return
+	}
+	var names []string
+	for name := range numbers.Reserved {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return numbers.Reserved[names[i]] < numbers.Reserved[names[j]] })
+	var nums, idents []string
+	for _, name := range names {
+		nums = append(nums, fmt.Sprint(numbers.Reserved[name]))
+		idents = append(idents, fmt.Sprintf("%q", ident(name)))
+	}
+	str.WriteString(indent + "reserved " + strings.Join(nums, ", ") + ";\n")
+	str.WriteString(indent + "reserved " + strings.Join(idents, ", ") + ";\n")
+	str.WriteString("\n")
+}
+
+// assign return the numbers of the names, the existing names keep their numbers, the removed
+// ones are reserved and the new ones are numbered after the max number used or reserved
+func (n *ProtoNumbers) assign(names []string, start int) *ProtoNumbers {
+	res := &ProtoNumbers{Numbers: map[string]int{}, Reserved: map[string]int{}}
+	next := start
+	if n != nil {
+		for name, number := range n.Numbers {
+			res.Reserved[name] = number
+			next = max(next, number+1)
+		}
+		for name, number := range n.Reserved {
+			res.Reserved[name] = number
+			next = max(next, number+1)
+		}
+	}
+	for _, name := range names {
+		if number, ok := res.Reserved[name]; ok {
+			res.Numbers[name] = number
+			delete(res.Reserved, name)
+			continue
+		}
+		// 19000 to 19999 are reserved by protobuf
+		if next >= 19000 && next <= 19999 {
+			next = 20000
+		}
+		res.Numbers[name] = next
+		next++
+	}
+	if len(res.Reserved) == 0 {
+		res.Reserved = nil
+	}
+	return res
+}
+
+// protoType return the proto type of the column, the wrapper type if nullable
+func protoType(dt *DataType, nullable bool) string {
+	typ := "string"
+	if dt != nil {
+		typ = protoScalar(dt)
+	}
+	if typ == "google.protobuf.Timestamp" || !nullable {
+		return typ
+	}
+	return map[string]string{
+		"int32": "google.protobuf.Int32Value", "uint32": "google.protobuf.UInt32Value",
+		"int64": "google.protobuf.Int64Value", "uint64": "google.protobuf.UInt64Value",
+		"bool": "google.protobuf.BoolValue", "float": "google.protobuf.FloatValue",
+		"double": "google.protobuf.DoubleValue", "string": "google.protobuf.StringValue",
+		"bytes": "google.protobuf.BytesValue",
+	}[typ]
+}
+
+func protoScalar(dt *DataType) string {
+	if dt.Name == "BOOL" || dt.Name == "BOOLEAN" {
+		return "bool"
+	}
+	name := foreignKeyType(dt)
+	if isIntegerType(name) {
+		typ := "int32"
+		if name == "BIGINT" {
+			typ = "int64"
+		}
+		if dt.IsUnsigned {
+			typ = "u" + typ
+		}
+		return typ
+	}
+	switch name {
+	case "FLOAT":
+		return "float"
+	case "DOUBLE":
+		return "double"
+	case "DATE", "DATETIME", "TIMESTAMP":
+		return "google.protobuf.Timestamp"
+	case "YEAR":
+		return "int32"
+	case "BINARY", "TINYBLOB", "BLOB", "MEDIUMBLOB", "LONGBLOB", "BIT", "GEOMETRY", "POINT", "LINESTRING",
+		"POLYGON", "MULTIPOINT", "MULTILINESTRING", "MULTIPOLYGON", "GEOMETRYCOLLECTION", "GEOMCOLLECTION":
+		return "bytes"
+	}
+	// DECIMAL is string to keep the precision
+	return "string"
+}
@@ -0,0 +1,112 @@
+package sqlparser
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGenerateProto(t *testing.T) {
+	Convey("TestGenerateProto", t, func() {
+		tables, err := From(`CREATE TABLE user_account (
+  id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  org_id int,
+  name varchar(64) NOT NULL COMMENT '用户名',
+  status enum('active','a b','a_b','','禁用') NOT NULL,
+  balance decimal(10,2),
+  created_at datetime NOT NULL,
+  avatar blob
+) COMMENT 'users';`)
+		So(err, ShouldBeNil)
+		res, lock, err := GenerateProto(tables, &ProtoOptions{GoPackage: "example.com/app/model"})
+		So(err, ShouldBeNil)
+		So(string(res), ShouldEqual, `// Code generated by sqlparser. DO NOT EDIT.
+
+syntax = "proto3";
+
+package model;
+
+import "google/protobuf/timestamp.proto";
+import "google/protobuf/wrappers.proto";
+
+option go_package = "example.com/app/model";
+
+// users
+message UserAccount {
+  uint64 id = 1;
+  google.protobuf.Int32Value org_id = 2;
+  // 用户名
+  string name = 3;
+  Status status = 4;
+  google.protobuf.StringValue balance = 5;
+  google.protobuf.Timestamp created_at = 6;
+  google.protobuf.BytesValue avatar = 7;
+
+  enum Status {
+    STATUS_UNSPECIFIED = 0;
+    STATUS_ACTIVE = 1; // "active"
+    STATUS_A_B = 2; // "a b"
+    STATUS_VALUE_3 = 3; // "a_b"
+    STATUS_EMPTY = 4; // ""
+    STATUS_VALUE_5 = 5; // "禁用"
+  }
+}
+`)
+
+		Convey("Lock", func() {
+			data, err := MarshalProtoLock(lock)
+			So(err, ShouldBeNil)
+			lock, err = UnmarshalProtoLock(data)
+			So(err, ShouldBeNil)
+			So(lock.Messages["user_account"].Numbers["avatar"], ShouldEqual, 7)
+
+			tables, err := From(`CREATE TABLE user_account (
+  id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  n int NOT NULL,
+  name varchar(64) NOT NULL,
+  status enum('active','x'),
+  org_id int
+);`)
+			So(err, ShouldBeNil)
+			res, lock, err := GenerateProto(tables, &ProtoOptions{Package: "app.v1", Lock: lock})
+			So(err, ShouldBeNil)
+			So(string(res), ShouldEqual, `// Code generated by sqlparser. DO NOT EDIT.
+
+syntax = "proto3";
+
+package app.v1;
+
+import "google/protobuf/wrappers.proto";
+
+message UserAccount {
+  reserved 5, 6, 7;
+  reserved "balance", "created_at", "avatar";
+
+  uint64 id = 1;
+  int32 n = 8;
+  string name = 3;
+  optional Status status = 4;
+  google.protobuf.Int32Value org_id = 2;
+
+  enum Status {
+    reserved 2, 3, 4, 5;
+    reserved "STATUS_A_B", "STATUS_VALUE_3", "STATUS_EMPTY", "STATUS_VALUE_5";
+
+    STATUS_UNSPECIFIED = 0;
+    STATUS_ACTIVE = 1; // "active"
+    STATUS_X = 6; // "x"
+  }
+}
+`)
+			So(lock.Messages["user_account"].Reserved, ShouldResemble, map[string]int{"balance": 5, "created_at": 6, "avatar": 7})
+			So(lock.Enums["user_account.status"].Numbers, ShouldResemble, map[string]int{"active": 1, "x": 6})
+		})
+
+		Convey("Conflict", func() {
+			tables, err := From("CREATE TABLE t (user_id int, userId int);")
+			So(err, ShouldBeNil)
+			_, _, err = GenerateProto(tables, nil)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
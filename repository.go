@@ -0,0 +1,206 @@
+package sqlparser
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+)
+
+// GenerateRepository generate the database/sql data access of the tables, a repository per table
+// with Insert, GetByPK, UpdateByPK, DeleteByPK and GetBy<Columns> per unique key. The rows are
+// scanned into the structs of GenerateGo, so generate both in a package with the same options.
+// The AUTO_INCREMENT and generated columns are not inserted, the AUTO_INCREMENT value is set
+// to the row after Insert. The methods by primary key are not generated without primary key.
+// The DATE, DATETIME and TIMESTAMP columns are scanned into time.Time with parseTime of the DSN
+func GenerateRepository(tables []*Table, opts *GoOptions) ([]byte, error) {
+	g := newGoGenerator(opts)
+	g.imports["context"] = true
+	g.imports["database/sql"] = true
+
+	var str strings.Builder
+	str.WriteString("// DBTX is the methods of *sql.DB, *sql.Conn and *sql.Tx used by the repositories\n")
+	str.WriteString("type DBTX interface {\n")
+	str.WriteString("\tExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)\n")
+	str.WriteString("\tQueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row\n")
+	str.WriteString("}\n\n")
+	for _, t := range tables {
+		g.writeRepository(&str, t)
+	}
+	return g.source(str.String())
+}
+
+func (g *goGenerator) writeRepository(str *strings.Builder, t *Table) {
+	name := pascalCase(t.Name, true)
+	repo := name + "Repository"
+	table := quoteIdent(t.Name)
+	str.WriteString(fmt.Sprintf("// %s is the data access of the table %s\n", repo, t.Name))
+	str.WriteString(fmt.Sprintf("type %s struct {\n\tdb DBTX\n}\n\n", repo))
+	str.WriteString(fmt.Sprintf("func New%s(db DBTX) *%s {\n\treturn &%s{db: db}\n}\n\n", repo, repo, repo))
+
+	// Insert
+	var columns, fields []string
+	var autoIncrement *Column
+	for _, col := range t.Columns {
+		if col.Constraint != nil && col.Constraint.AutoIncrement {
+			autoIncrement = col
+		}
+		if col.Constraint != nil && (col.Constraint.AutoIncrement || col.Constraint.Generated != "") {
+			continue
+		}
+		columns = append(columns, quoteIdent(col.Name))
+		fields = append(fields, "row."+pascalCase(col.Name, true))
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table, strings.Join(columns, ", "), placeholders(len(columns)))
+	str.WriteString("// Insert insert the row")
+	if autoIncrement != nil {
+		str.WriteString(fmt.Sprintf(", %s is set to the AUTO_INCREMENT value", pascalCase(autoIncrement.Name, true)))
+	}
+	str.WriteString(fmt.Sprintf("\nfunc (r *%s) Insert(ctx context.Context, row *%s) error {\n", repo, name))
+	if autoIncrement != nil && isGoInteger(g.fieldType(t, autoIncrement)) {
+		str.WriteString(fmt.Sprintf("\tres, err := r.db.ExecContext(ctx, %q%s)\n", query, joinArgs(fields)))
+		str.WriteString("\tif err != nil {\n\t\treturn err\n\t}\n")
+		str.WriteString("\tid, err := res.LastInsertId()\n")
+		str.WriteString("\tif err != nil {\n\t\treturn err\n\t}\n")
+		str.WriteString(fmt.Sprintf("\trow.%s = %s(id)\n\treturn nil\n}\n\n", pascalCase(autoIncrement.Name, true), g.fieldType(t, autoIncrement)))
+	} else {
+		str.WriteString(fmt.Sprintf("\t_, err := r.db.ExecContext(ctx, %q%s)\n\treturn err\n}\n\n", query, joinArgs(fields)))
+	}
+
+	// the columns to select and the fields to scan
+	var selects, scans []string
+	for _, col := range t.Columns {
+		selects = append(selects, quoteIdent(col.Name))
+		scans = append(scans, "&row."+pascalCase(col.Name, true))
+	}
+	get := func(method, doc string, keys []string) {
+		params, args, where := g.keyParams(t, keys)
+		query := fmt.Sprintf("SELECT %s FROM %s WHERE %s", strings.Join(selects, ", "), table, where)
+		str.WriteString(fmt.Sprintf("// %s return the row %s, sql.ErrNoRows if not exists\n", method, doc))
+		str.WriteString(fmt.Sprintf("func (r *%s) %s(ctx context.Context, %s) (*%s, error) {\n", repo, method, params, name))
+		str.WriteString(fmt.Sprintf("\trow := &%s{}\n", name))
+		str.WriteString(fmt.Sprintf("\terr := r.db.QueryRowContext(ctx, %q%s).Scan(%s)\n", query, joinArgs(args), strings.Join(scans, ", ")))
+		str.WriteString("\tif err != nil {\n\t\treturn nil, err\n\t}\n\treturn row, nil\n}\n\n")
+	}
+
+	if pk := primaryKeyColumns(t); len(pk) != 0 {
+		get("GetByPK", "by primary key", pk)
+
+		// UpdateByPK
+		var sets, args []string
+		for _, col := range t.Columns {
+			if containsName(pk, col.Name) || col.Constraint != nil && (col.Constraint.AutoIncrement || col.Constraint.Generated != "") {
+				continue
+			}
+			sets = append(sets, quoteIdent(col.Name)+" = ?")
+			args = append(args, "row."+pascalCase(col.Name, true))
+		}
+		var wheres []string
+		for _, key := range pk {
+			wheres = append(wheres, quoteIdent(key)+" = ?")
+			args = append(args, "row."+pascalCase(key, true))
+		}
+		if len(sets) != 0 {
+			query := fmt.Sprintf("UPDATE %s SET %s WHERE %s", table, strings.Join(sets, ", "), strings.Join(wheres, " AND "))
+			str.WriteString("// UpdateByPK update the columns of the row by primary key, except the generated ones\n")
+			str.WriteString(fmt.Sprintf("func (r *%s) UpdateByPK(ctx context.Context, row *%s) error {\n", repo, name))
+			str.WriteString(fmt.Sprintf("\t_, err := r.db.ExecContext(ctx, %q%s)\n\treturn err\n}\n\n", query, joinArgs(args)))
+		}
+
+		// DeleteByPK
+		params, args, where := g.keyParams(t, pk)
+		query := fmt.Sprintf("DELETE FROM %s WHERE %s", table, where)
+		str.WriteString("// DeleteByPK delete the row by primary key\n")
+		str.WriteString(fmt.Sprintf("func (r *%s) DeleteByPK(ctx context.Context, %s) error {\n", repo, params))
+		str.WriteString(fmt.Sprintf("\t_, err := r.db.ExecContext(ctx, %q%s)\n\treturn err\n}\n\n", query, joinArgs(args)))
+	}
+
+	for _, keys := range uniqueKeyColumns(t) {
+		var words []string
+		for _, key := range keys {
+			words = append(words, pascalCase(key, true))
+		}
+		get("GetBy"+strings.Join(words, "And"), "by the unique key ("+strings.Join(keys, ", ")+")", keys)
+	}
+}
+
+// keyParams return the parameters, the arguments and the condition of the key columns,
+// a NULL argument matches the NULL value
+func (g *goGenerator) keyParams(t *Table, keys []string) (string, []string, string) {
+	var params, args, wheres []string
+	for _, key := range keys {
+		col := t.Column(key)
+		// camel case with the initialisms like orgID
+		words := splitWords(key)
+		name := strings.ToLower(words[0]) + pascalCase(strings.Join(words[1:], "_"), true)
+		if token.IsKeyword(name) || name == "ctx" || name == "row" || name == "r" || name == "err" {
+			name += "Value"
+		}
+		params = append(params, name+" "+g.fieldType(t, col))
+		args = append(args, name)
+		// NULL = NULL is not true, the nullable columns of the unique keys are compared by <=>
+		op := " = ?"
+		if columnNullable(t, col) {
+			op = " <=> ?"
+		}
+		wheres = append(wheres, quoteIdent(col.Name)+op)
+	}
+	return strings.Join(params, ", "), args, strings.Join(wheres, " AND ")
+}
+
+// primaryKeyColumns return the columns of PRIMARY KEY, of the table or the column definition
+func primaryKeyColumns(t *Table) []string {
+	if pk := primaryKey(t); pk != nil {
+		return pk.ColumnPrimaryKey
+	}
+	for _, col := range t.Columns {
+		if col.Constraint != nil && (col.Constraint.Primary || col.Constraint.Key) {
+			return []string{col.Name}
+		}
+	}
+	return nil
+}
+
+// uniqueKeyColumns return the columns of the unique keys, of the table or the column definitions,
+// without the duplicated ones
+func uniqueKeyColumns(t *Table) [][]string {
+	var res [][]string
+	add := func(columns []string) {
+		for _, keys := range res {
+			if namesEqual(keys, columns) {
+				return
+			}
+		}
+		res = append(res, columns)
+	}
+	for _, col := range t.Columns {
+		if col.Constraint != nil && col.Constraint.Unique {
+			add([]string{col.Name})
+		}
+	}
+	for _, cons := range t.Constraints {
+		if cons.ColumnUniqueKey != nil {
+			add(cons.ColumnUniqueKey)
+		}
+	}
+	return res
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// joinArgs return the arguments after the query
+func joinArgs(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return ", " + strings.Join(args, ", ")
+}
+
+func isGoInteger(typ string) bool {
+	switch typ {
+	case "int8", "int16", "int32", "int64", "uint8", "uint16", "uint32", "uint64":
+		return true
+	}
+	return false
+}
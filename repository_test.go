@@ -0,0 +1,61 @@
+package sqlparser
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGenerateRepository(t *testing.T) {
+	Convey("TestGenerateRepository", t, func() {
+		tables, err := From(`CREATE TABLE user_account (
+  id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  org_id int,
+  email varchar(64) NOT NULL UNIQUE,
+  name varchar(64) NOT NULL,
+  ` + "`type`" + ` enum('a','b') NOT NULL,
+  full_name varchar(130) GENERATED ALWAYS AS (concat(name)) VIRTUAL,
+  UNIQUE KEY uk_org_name (org_id, name)
+);
+CREATE TABLE tag (post_id int NOT NULL, tag varchar(10) NOT NULL, PRIMARY KEY (post_id, tag));
+CREATE TABLE log (msg text);
+CREATE TABLE post (id int AUTO_INCREMENT PRIMARY KEY, updated_at timestamp NULL ON UPDATE CURRENT_TIMESTAMP);`)
+		So(err, ShouldBeNil)
+		res, err := GenerateRepository(tables, nil)
+		So(err, ShouldBeNil)
+		src := string(res)
+
+		Convey("Insert", func() {
+			So(src, ShouldContainSubstring, "func (r *UserAccountRepository) Insert(ctx context.Context, row *UserAccount) error {\n"+
+				"\tres, err := r.db.ExecContext(ctx, \"INSERT INTO `user_account` (`org_id`, `email`, `name`, `type`) VALUES (?, ?, ?, ?)\", "+
+				"row.OrgID, row.Email, row.Name, row.Type)\n")
+			So(src, ShouldContainSubstring, "\trow.ID = uint64(id)\n")
+			So(src, ShouldContainSubstring, "func (r *LogRepository) Insert(ctx context.Context, row *Log) error {\n"+
+				"\t_, err := r.db.ExecContext(ctx, \"INSERT INTO `log` (`msg`) VALUES (?)\", row.Msg)\n\treturn err\n}\n")
+			// ON UPDATE CURRENT_TIMESTAMP is not AUTO_INCREMENT
+			So(src, ShouldContainSubstring, "\tres, err := r.db.ExecContext(ctx, \"INSERT INTO `post` (`updated_at`) VALUES (?)\", row.UpdatedAt)\n")
+			So(src, ShouldContainSubstring, "\trow.ID = int32(id)\n")
+		})
+
+		Convey("PK", func() {
+			So(src, ShouldContainSubstring, "func (r *UserAccountRepository) GetByPK(ctx context.Context, id uint64) (*UserAccount, error) {\n"+
+				"\trow := &UserAccount{}\n"+
+				"\terr := r.db.QueryRowContext(ctx, \"SELECT `id`, `org_id`, `email`, `name`, `type`, `full_name` FROM `user_account` WHERE `id` = ?\", id)."+
+				"Scan(&row.ID, &row.OrgID, &row.Email, &row.Name, &row.Type, &row.FullName)\n")
+			So(src, ShouldContainSubstring, "\t_, err := r.db.ExecContext(ctx, \"UPDATE `user_account` SET `org_id` = ?, `email` = ?, `name` = ?, `type` = ? WHERE `id` = ?\", "+
+				"row.OrgID, row.Email, row.Name, row.Type, row.ID)\n")
+			So(src, ShouldContainSubstring, "func (r *TagRepository) DeleteByPK(ctx context.Context, postID int32, tag string) error {\n"+
+				"\t_, err := r.db.ExecContext(ctx, \"DELETE FROM `tag` WHERE `post_id` = ? AND `tag` = ?\", postID, tag)\n")
+			// no column to update
+			So(src, ShouldNotContainSubstring, "func (r *TagRepository) UpdateByPK")
+			So(src, ShouldNotContainSubstring, "func (r *LogRepository) GetByPK")
+		})
+
+		Convey("UniqueKey", func() {
+			So(src, ShouldContainSubstring, "func (r *UserAccountRepository) GetByEmail(ctx context.Context, email string) (*UserAccount, error) {\n")
+			So(src, ShouldContainSubstring, "func (r *UserAccountRepository) GetByOrgIDAndName(ctx context.Context, orgID sql.NullInt32, name string) (*UserAccount, error) {\n")
+			So(src, ShouldContainSubstring, "FROM `user_account` WHERE `org_id` <=> ? AND `name` = ?\", orgID, name)")
+			So(src, ShouldContainSubstring, "FROM `user_account` WHERE `email` = ?\", email)")
+		})
+	})
+}
@@ -0,0 +1,299 @@
+package sqlparser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The levels of Risk
+const (
+	RiskDestructive = "DESTRUCTIVE" // the rows or values may be lost
+	RiskWarning     = "WARNING"     // the statement may fail or break the queries of application
+)
+
+// Risk is a dangerous change of a statement found by Analyze
+type Risk struct {
+	Level   string
+	File    string
+	Span    Span // zero if the statement is analyzed without location
+	Table   *TableName
+	Name    string // the column, index or partition, empty for the table
+	Message string
+}
+
+func (r *Risk) String() string {
+	str := r.Level + ": " + r.Message
+	if r.Span != (Span{}) {
+		str = fmt.Sprintf("line %d:%d %s", r.Span.Line, r.Span.Column, str)
+	}
+	if r.File != "" {
+		str = r.File + " " + str
+	}
+	return str
+}
+
+// Analyze return the risks of the statement against the catalog before it is applied:
+// dropped or truncated tables, dropped columns, narrowed types, changed signedness,
+// NULL to NOT NULL without default, dropped or renamed unique keys and charset conversions.
+// The catalog is not changed, Apply the statement after
+func (c *Catalog) Analyze(stmt interface{}) []*Risk {
+	var file string
+	var span Span
+	if s, ok := stmt.(*Statement); ok {
+		file, span = s.File, s.Span
+		stmt = s.Node
+	}
+	res := c.analyze(stmt)
+	for _, r := range res {
+		r.File, r.Span = file, span
+	}
+	return res
+}
+
+// AnalyzeStatements analyze and apply the statements in turn,
+// return the risks found before the first statement failed to apply
+func (c *Catalog) AnalyzeStatements(stmts []*Statement) ([]*Risk, error) {
+	var res []*Risk
+	for _, stmt := range stmts {
+		res = append(res, c.Analyze(stmt)...)
+		if err := c.Apply(stmt); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+func (c *Catalog) analyze(stmt interface{}) []*Risk {
+	var res []*Risk
+	switch s := stmt.(type) {
+	case *DropDatabase:
+		if schema := c.Schema(s.Name); schema != nil && len(schema.Tables) != 0 {
+			res = append(res, &Risk{Level: RiskDestructive,
+				Message: fmt.Sprintf("database '%s' is dropped with %d tables", s.Name, len(schema.Tables))})
+		}
+	case *DropTable:
+		if s.Temporary {
+			break
+		}
+		for _, name := range s.Tables {
+			if c.Table(name.Schema, name.Name) != nil {
+				res = append(res, &Risk{Level: RiskDestructive, Table: name,
+					Message: fmt.Sprintf("table '%s' is dropped", name)})
+			}
+		}
+	case *TruncateTable:
+		res = append(res, &Risk{Level: RiskDestructive, Table: s.Table,
+			Message: fmt.Sprintf("table '%s' is truncated", s.Table)})
+	case *RenameTable:
+		for _, clause := range s.Clauses {
+			res = append(res, &Risk{Level: RiskWarning, Table: clause.From,
+				Message: fmt.Sprintf("table '%s' is renamed to '%s'", clause.From, clause.To)})
+		}
+	case *AlterTable:
+		res = c.analyzeAlterTable(s)
+	case *DropIndex:
+		res = c.analyzeAlterTable(&AlterTable{
+			Table: s.Table,
+			Specs: []*AlterSpecification{{Action: AlterDropIndex, Name: s.Name}},
+		})
+	}
+	return res
+}
+
+// analyzeAlterTable analyze the specifications in turn on a copy of the table,
+// so a specification sees the changes of the previous ones
+func (c *Catalog) analyzeAlterTable(stmt *AlterTable) []*Risk {
+	origin := c.Table(stmt.Table.Schema, stmt.Table.Name)
+	if origin == nil {
+		return nil
+	}
+	s := c.Schema(stmt.Table.Schema)
+	t := cloneTable(origin)
+	var res []*Risk
+	add := func(level, name, format string, args ...interface{}) {
+		res = append(res, &Risk{Level: level, Table: stmt.Table, Name: name, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, spec := range stmt.Specs {
+		switch spec.Action {
+		case AlterRenameTable:
+			add(RiskWarning, "", "table '%s' is renamed to '%s'", stmt.Table, spec.RenameTo)
+			continue
+		case AlterDropColumn:
+			if t.Column(spec.Name) != nil {
+				add(RiskDestructive, spec.Name, "column '%s' is dropped", spec.Name)
+			}
+		case AlterRenameColumn:
+			if t.Column(spec.Name) != nil {
+				add(RiskWarning, spec.Name, "column '%s' is renamed to '%s'", spec.Name, spec.NewName)
+			}
+		case AlterChangeColumn, AlterModifyColumn:
+			old := t.Column(spec.Name)
+			if old == nil || spec.Column == nil {
+				break
+			}
+			col := spec.Column
+			if !strings.EqualFold(old.Name, col.Name) {
+				add(RiskWarning, old.Name, "column '%s' is renamed to '%s'", old.Name, col.Name)
+			}
+			before, after := columnCharset(s, t, old), columnCharset(s, t, col)
+			if level, msg := columnTypeRisk(before, after); msg != "" {
+				add(level, old.Name, "%s", msg)
+			}
+			if (old.Constraint == nil || !old.Constraint.NotNull) && col.Constraint != nil && col.Constraint.NotNull &&
+				(col.Constraint.DefaultValue == nil || !col.Constraint.DefaultValue.Is) {
+				add(RiskWarning, old.Name, "column '%s' becomes NOT NULL without a default", old.Name)
+			}
+			if before.charset != "" && after.charset != "" && normalizeCharset(before.charset) != normalizeCharset(after.charset) {
+				add(RiskWarning, old.Name, "column '%s' is converted from charset %s to %s", old.Name, before.charset, after.charset)
+			}
+		case AlterDropPrimaryKey:
+			if primaryKey(t) != nil {
+				add(RiskWarning, "PRIMARY", "primary key is dropped")
+			}
+		case AlterDropIndex, AlterDropConstraint:
+			if cons := findConstraint(t, spec.Name, isIndex); cons != nil && cons.ColumnPrimaryKey != nil {
+				add(RiskWarning, cons.Name, "primary key is dropped")
+			} else if cons != nil && cons.ColumnUniqueKey != nil {
+				add(RiskWarning, cons.Name, "unique key '%s' is dropped", cons.Name)
+			}
+		case AlterRenameIndex:
+			if cons := findConstraint(t, spec.Name, isIndex); cons != nil && cons.ColumnUniqueKey != nil {
+				add(RiskWarning, cons.Name, "unique key '%s' is renamed to '%s'", cons.Name, spec.NewName)
+			}
+		case AlterConvertCharset:
+			if spec.Options != nil {
+				add(RiskWarning, "", "table '%s' is converted to charset %s", stmt.Table, spec.Options.Charset)
+			}
+		case AlterDropPartition:
+			add(RiskDestructive, spec.Name, "partition '%s' is dropped with its rows", spec.Name)
+		}
+		if alterSpecification(t, spec) != "" {
+			// the statement fails to apply, the rest are not analyzed
+			break
+		}
+	}
+	return res
+}
+
+// columnTypeRisk compare the data types, the narrowed types and signedness are destructive,
+// the other changes of the type kind are warned
+func columnTypeRisk(old, col typedColumn) (string, string) {
+	a, b := old.DataType, col.DataType
+	if a == nil || b == nil {
+		return "", ""
+	}
+	ta, tb := foreignKeyType(a), foreignKeyType(b)
+	ka, kb := typeKind(ta), typeKind(tb)
+	if ka != kb || (ka == "" && ta != tb) {
+		return RiskWarning, fmt.Sprintf("column '%s' changes type from %s to %s", old.Name, a.Source, b.Source)
+	}
+	narrowed := fmt.Sprintf("column '%s' is narrowed from %s to %s", old.Name, a.Source, b.Source)
+
+	switch ka {
+	case "integer", "decimal", "float":
+		// INT UNSIGNED fits in BIGINT
+		widened := ka == "integer" && a.IsUnsigned && typeRank(tb) > typeRank(ta)
+		if a.IsUnsigned != b.IsUnsigned && !widened {
+			from, to := "signed", "unsigned"
+			if a.IsUnsigned {
+				from, to = to, from
+			}
+			return RiskDestructive, fmt.Sprintf("column '%s' changes from %s to %s", old.Name, from, to)
+		}
+	}
+	switch ka {
+	case "integer":
+		if typeRank(tb) < typeRank(ta) {
+			return RiskDestructive, narrowed
+		}
+	case "float":
+		if ta == "DOUBLE" && tb == "FLOAT" {
+			return RiskDestructive, narrowed
+		}
+	case "decimal":
+		pa, sa := decimalPrecision(a)
+		pb, sb := decimalPrecision(b)
+		if pb-sb < pa-sa || sb < sa {
+			return RiskDestructive, narrowed
+		}
+	case "character":
+		// the lengths are in characters, the capacities of the TEXT types in bytes
+		if stringCapacity(tb, b, charsetMaxBytes(col.charset, "")) < stringCapacity(ta, a, charsetMaxBytes(old.charset, "")) {
+			return RiskDestructive, narrowed
+		}
+	case "binary":
+		if stringCapacity(tb, b, 1) < stringCapacity(ta, a, 1) {
+			return RiskDestructive, narrowed
+		}
+	case "collection":
+		if ta != tb {
+			return RiskWarning, fmt.Sprintf("column '%s' changes type from %s to %s", old.Name, a.Source, b.Source)
+		}
+		var lost []string
+		for _, option := range a.CollectionOptions {
+			if !containsName(b.CollectionOptions, option) {
+				lost = append(lost, "'"+option+"'")
+			}
+		}
+		if len(lost) != 0 {
+			return RiskDestructive, fmt.Sprintf("column '%s' loses the %s values %s", old.Name, ta, strings.Join(lost, ", "))
+		}
+	}
+	return "", ""
+}
+
+// typeKind return the kind of the types converted without loss if widened, empty for the others
+func typeKind(name string) string {
+	switch {
+	case isIntegerType(name):
+		return "integer"
+	}
+	switch name {
+	case "DECIMAL":
+		return "decimal"
+	case "FLOAT", "DOUBLE":
+		return "float"
+	case "CHAR", "TINYTEXT", "TEXT", "MEDIUMTEXT", "LONGTEXT", "LONG":
+		return "character"
+	case "BINARY", "TINYBLOB", "BLOB", "MEDIUMBLOB", "LONGBLOB":
+		return "binary"
+	case "ENUM", "SET":
+		return "collection"
+	}
+	return ""
+}
+
+func typeRank(name string) int {
+	for i, t := range []string{"TINYINT", "SMALLINT", "MEDIUMINT", "INT", "BIGINT"} {
+		if t == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// stringCapacity return the max length of the character or binary type,
+// the capacities in bytes are divided by the max bytes of a character
+func stringCapacity(name string, dt *DataType, maxBytes int) int64 {
+	switch name {
+	case "CHAR", "BINARY":
+		if dt.HasLength {
+			return int64(dt.Length)
+		}
+		if dt.Name == "VARCHAR" || dt.Name == "VARBINARY" {
+			return 0
+		}
+		return 1
+	case "TINYTEXT", "TINYBLOB":
+		return (1<<8 - 1) / int64(maxBytes)
+	case "TEXT", "BLOB":
+		if dt.HasLength {
+			return int64(dt.Length)
+		}
+		return (1<<16 - 1) / int64(maxBytes)
+	case "MEDIUMTEXT", "MEDIUMBLOB", "LONG":
+		return (1<<24 - 1) / int64(maxBytes)
+	}
+	return (1<<32 - 1) / int64(maxBytes)
+}
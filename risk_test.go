@@ -0,0 +1,109 @@
+package sqlparser
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAnalyze(t *testing.T) {
+	Convey("TestAnalyze", t, func() {
+		c := new(Catalog)
+		So(replay(c, `CREATE TABLE user (id bigint PRIMARY KEY, name varchar(255), age int unsigned, bio text,
+  price decimal(10,2), score double, role enum('admin','user'), email varchar(64), nick varchar(16),
+  UNIQUE KEY uk_email (email), KEY idx_nick (nick)) DEFAULT CHARSET=utf8mb4;
+CREATE TABLE log (id int);`), ShouldBeNil)
+
+		analyze := func(str string) []string {
+			stmts, err := Parse(str)
+			So(err, ShouldBeNil)
+			risks, err := c.AnalyzeStatements(stmts)
+			So(err, ShouldBeNil)
+			var res []string
+			for _, r := range risks {
+				res = append(res, r.String())
+			}
+			return res
+		}
+
+		Convey("Columns", func() {
+			So(analyze(`ALTER TABLE user MODIFY id int, MODIFY name varchar(50), MODIFY age int,
+  MODIFY bio tinytext, MODIFY price decimal(10,3), MODIFY score float, MODIFY role enum('user', 'guest');`), ShouldResemble, []string{
+				"line 1:0 DESTRUCTIVE: column 'id' is narrowed from BIGINT to INT",
+				"line 1:0 DESTRUCTIVE: column 'name' is narrowed from VARCHAR(255) to VARCHAR(50)",
+				"line 1:0 DESTRUCTIVE: column 'age' changes from unsigned to signed",
+				"line 1:0 DESTRUCTIVE: column 'bio' is narrowed from TEXT to TINYTEXT",
+				"line 1:0 DESTRUCTIVE: column 'price' is narrowed from DECIMAL(10,2) to DECIMAL(10,3)",
+				"line 1:0 DESTRUCTIVE: column 'score' is narrowed from DOUBLE to FLOAT",
+				"line 1:0 DESTRUCTIVE: column 'role' loses the ENUM values 'admin'",
+			})
+		})
+
+		Convey("Widened", func() {
+			So(analyze(`ALTER TABLE user MODIFY id bigint, MODIFY name text, MODIFY age bigint,
+  MODIFY price decimal(12,2), MODIFY role enum('admin', 'user', 'guest'), ADD COLUMN x int;`), ShouldBeEmpty)
+		})
+
+		Convey("Bytes and characters", func() {
+			So(analyze(`ALTER TABLE user MODIFY name varchar(20000), MODIFY bio varchar(16383);
+ALTER TABLE user MODIFY name text, MODIFY nick text CHARACTER SET latin1, MODIFY role set('admin', 'user');`), ShouldResemble, []string{
+				"line 2:0 DESTRUCTIVE: column 'name' is narrowed from VARCHAR(20000) to TEXT",
+				"line 2:0 WARNING: column 'nick' is converted from charset utf8mb4 to latin1",
+				"line 2:0 WARNING: column 'role' changes type from ENUM('admin','user') to SET('admin','user')",
+			})
+		})
+
+		Convey("Nullable", func() {
+			So(analyze(`ALTER TABLE user MODIFY name varchar(255) NOT NULL, MODIFY email varchar(64) NOT NULL DEFAULT '';
+ALTER TABLE user CHANGE nick nickname int NOT NULL DEFAULT 0;`), ShouldResemble, []string{
+				"line 1:0 WARNING: column 'name' becomes NOT NULL without a default",
+				"line 2:0 WARNING: column 'nick' is renamed to 'nickname'",
+				"line 2:0 WARNING: column 'nick' changes type from VARCHAR(16) to INT",
+			})
+		})
+
+		Convey("Keys and charset", func() {
+			risks := analyze(`ALTER TABLE user RENAME INDEX uk_email TO uk_mail, RENAME INDEX idx_nick TO idx_nickname;
+ALTER TABLE user DROP INDEX uk_mail, DROP INDEX idx_nickname;
+ALTER TABLE user DROP PRIMARY KEY;
+ALTER TABLE user MODIFY name varchar(255) CHARACTER SET latin1;
+ALTER TABLE user CONVERT TO CHARACTER SET latin1;`)
+			So(risks, ShouldResemble, []string{
+				"line 1:0 WARNING: unique key 'uk_email' is renamed to 'uk_mail'",
+				"line 2:0 WARNING: unique key 'uk_mail' is dropped",
+				"line 3:0 WARNING: primary key is dropped",
+				"line 4:0 WARNING: column 'name' is converted from charset utf8mb4 to latin1",
+				"line 5:0 WARNING: table 'user' is converted to charset latin1",
+			})
+		})
+
+		Convey("Tables", func() {
+			stmts, err := Parse(`ALTER TABLE user DROP COLUMN bio; RENAME TABLE log TO log2; TRUNCATE log2;
+DROP TABLE log2, missing;`)
+			So(err, ShouldBeNil)
+			risks, err := c.AnalyzeStatements(stmts)
+			So(err, ShouldNotBeNil) // missing table
+			So(len(risks), ShouldEqual, 4)
+			So(risks[0], ShouldResemble, &Risk{Level: RiskDestructive, Span: stmts[0].Span,
+				Table: &TableName{Name: "user"}, Name: "bio", Message: "column 'bio' is dropped"})
+			So(risks[1].Message, ShouldEqual, "table 'log' is renamed to 'log2'")
+			So(risks[2].Message, ShouldEqual, "table 'log2' is truncated")
+			So(risks[3].Message, ShouldEqual, "table 'log2' is dropped")
+
+			So(replay(c, "CREATE DATABASE app; CREATE TABLE app.t (id int)"), ShouldBeNil)
+			So(c.Analyze(&DropDatabase{Name: "app"})[0].String(), ShouldEqual, "DESTRUCTIVE: database 'app' is dropped with 1 tables")
+		})
+
+		Convey("Syntax error", func() {
+			// the analysis fails instead of missing the DROP TABLE after the syntax error
+			stmts, err := Parse("ALTER TABLE log ADD COLUMN y int,;\nDROP TABLE log;")
+			So(err, ShouldNotBeNil)
+			So(stmts, ShouldBeEmpty)
+			errs, ok := err.(SyntaxErrors)
+			So(ok, ShouldBeTrue)
+			So(errs[0].Line, ShouldEqual, 1)
+			So(errs[0].Column, ShouldEqual, 33)
+			So(err.Error(), ShouldStartWith, "line 1:33 ")
+		})
+	})
+}
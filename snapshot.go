@@ -0,0 +1,101 @@
+package sqlparser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SnapshotVersion is the version of the snapshot format, increased on incompatible changes
+const SnapshotVersion = 1
+
+// Snapshot is the serialized schema of a catalog, stable to commit and compare in review.
+// The fields are named in snake case and the empty ones are omitted,
+// the token number of DataType is not kept and restored by the type name
+type Snapshot struct {
+	Version int       `json:"version" yaml:"version"`
+	Schemas []*Schema `json:"schemas,omitempty" yaml:"schemas,omitempty"`
+}
+
+// Snapshot return the snapshot of the catalog
+func (c *Catalog) Snapshot() *Snapshot {
+	return &Snapshot{Version: SnapshotVersion, Schemas: c.Schemas}
+}
+
+// Catalog return the catalog of the snapshot, error if the version is not supported
+func (s *Snapshot) Catalog() (*Catalog, error) {
+	if s.Version != SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d, want %d", s.Version, SnapshotVersion)
+	}
+	for _, schema := range s.Schemas {
+		for _, t := range schema.Tables {
+			for _, col := range t.Columns {
+				if col.DataType != nil {
+					col.DataType.Number = tokenNumber(col.DataType.Name)
+				}
+			}
+		}
+	}
+	return &Catalog{Schemas: s.Schemas}, nil
+}
+
+// MarshalSnapshotJSON return the indented JSON snapshot of the catalog, ending with a newline
+func MarshalSnapshotJSON(c *Catalog) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(c.Snapshot()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalSnapshotJSON return the catalog of the JSON snapshot
+func UnmarshalSnapshotJSON(data []byte) (*Catalog, error) {
+	var s Snapshot
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&s); err != nil {
+		return nil, err
+	}
+	return s.Catalog()
+}
+
+// MarshalSnapshotYAML return the YAML snapshot of the catalog
+func MarshalSnapshotYAML(c *Catalog) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(c.Snapshot()); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalSnapshotYAML return the catalog of the YAML snapshot
+func UnmarshalSnapshotYAML(data []byte) (*Catalog, error) {
+	var s Snapshot
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&s); err != nil {
+		return nil, err
+	}
+	return s.Catalog()
+}
+
+// tokenNumber return the lexer token of the type name, 0 if not a token
+func tokenNumber(name string) int {
+	MySqlLexerInit()
+	for i, symbol := range MySqlLexerLexerStaticData.SymbolicNames {
+		if symbol == name {
+			return i
+		}
+	}
+	return 0
+}
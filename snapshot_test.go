@@ -0,0 +1,66 @@
+package sqlparser
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSnapshot(t *testing.T) {
+	Convey("TestSnapshot", t, func() {
+		c := new(Catalog)
+		So(replay(c, `CREATE DATABASE app CHARACTER SET utf8mb4; USE app;
+SET sql_mode = 'ANSI_QUOTES';
+CREATE TABLE org (id bigint unsigned PRIMARY KEY AUTO_INCREMENT, name varchar(64) NOT NULL DEFAULT '' COMMENT 'it''s',
+  kind enum('a','b') CHARACTER SET latin1, price decimal(10,2), created_at timestamp DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE KEY uk_name (name), FULLTEXT KEY ft_name (name), CHECK (price > 0))
+  ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='组织' PARTITION BY HASH (id) PARTITIONS 4;
+CREATE TABLE user (id int PRIMARY KEY, org_id bigint unsigned,
+  CONSTRAINT fk_org FOREIGN KEY (org_id) REFERENCES org (id) ON DELETE CASCADE);
+CREATE VIEW v AS SELECT id FROM user;
+CREATE TABLE other.t (id int);`), ShouldBeNil)
+
+		Convey("JSON", func() {
+			data, err := MarshalSnapshotJSON(c)
+			So(err, ShouldBeNil)
+			So(string(data), ShouldStartWith, "{\n  \"version\": 1,\n  \"schemas\": [\n")
+			So(string(data), ShouldContainSubstring, `"comment": "组织"`)
+			So(string(data), ShouldNotContainSubstring, `"number"`)
+			again, err := MarshalSnapshotJSON(c)
+			So(err, ShouldBeNil)
+			So(string(again), ShouldEqual, string(data))
+
+			res, err := UnmarshalSnapshotJSON(data)
+			So(err, ShouldBeNil)
+			So(res, ShouldResemble, c)
+			So(res.Schema("other").Implicit, ShouldBeTrue)
+
+			_, err = UnmarshalSnapshotJSON([]byte(`{"version": 2}`))
+			So(err, ShouldNotBeNil)
+			_, err = UnmarshalSnapshotJSON([]byte(`{"version": 1, "tables": []}`))
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("YAML", func() {
+			data, err := MarshalSnapshotYAML(c)
+			So(err, ShouldBeNil)
+			So(string(data), ShouldStartWith, "version: 1\nschemas:\n")
+			So(string(data), ShouldContainSubstring, "      - schema: app\n        name: org\n")
+
+			res, err := UnmarshalSnapshotYAML(data)
+			So(err, ShouldBeNil)
+			So(res, ShouldResemble, c)
+			So(res.Table("app", "org").Column("id").DataType.Number, ShouldEqual, MySqlLexerBIGINT)
+		})
+
+		Convey("String", func() {
+			str := c.Table("app", "org").String() + c.Table("app", "user").String()
+			So(strings.Split(str, "\n"), ShouldContain, "unique key uk_name: name")
+			So(strings.Split(str, "\n"), ShouldContain, "fulltext index ft_name: name")
+			So(strings.Split(str, "\n"), ShouldContain, "check org_chk_1: price > 0")
+			So(strings.Split(str, "\n"), ShouldContain, "foreign key fk_org: org_id references app.org (id)")
+			So(strings.Count(str, "primary key:"), ShouldEqual, 2)
+		})
+	})
+}
@@ -2,18 +2,74 @@ package sqlparser
 
 import (
 	"strings"
+	"time"
 )
 
 type TableConstraint struct {
-	ColumnPrimaryKey []string
-	ColumnUniqueKey  []string // col names, not index name
-	ColumnForeignKey []string
+	ColumnPrimaryKey []string `json:"primary_key,omitempty" yaml:"primary_key,omitempty"`
+	ColumnUniqueKey  []string `json:"unique_key,omitempty" yaml:"unique_key,omitempty"` // col names, not index name
+	ColumnForeignKey []string `json:"foreign_key,omitempty" yaml:"foreign_key,omitempty"`
+	ColumnIndex      []string `json:"index,omitempty" yaml:"index,omitempty"` // col names of KEY, INDEX, FULLTEXT or SPATIAL index
+
+	Name      string     `json:"name,omitempty" yaml:"name,omitempty"`             // the constraint or index name, empty if not named
+	IndexKind string     `json:"index_kind,omitempty" yaml:"index_kind,omitempty"` // FULLTEXT or SPATIAL, empty for the others
+	Reference *Reference `json:"reference,omitempty" yaml:"reference,omitempty"`   // the referenced table of foreign key
+	Check     string     `json:"check,omitempty" yaml:"check,omitempty"`           // the expression of check constraint
+}
+
+// Reference is the REFERENCES clause of foreign key
+type Reference struct {
+	Table    *TableName `json:"table,omitempty" yaml:"table,omitempty"`
+	Columns  []string   `json:"columns,omitempty" yaml:"columns,omitempty"`
+	OnDelete string     `json:"on_delete,omitempty" yaml:"on_delete,omitempty"` // RESTRICT, CASCADE, SET NULL, NO ACTION or SET DEFAULT, empty if not specified
+	OnUpdate string     `json:"on_update,omitempty" yaml:"on_update,omitempty"`
+}
+
+// TableOptions is the options after the create definitions, empty if not specified
+type TableOptions struct {
+	Engine        string `json:"engine,omitempty" yaml:"engine,omitempty"`
+	Charset       string `json:"charset,omitempty" yaml:"charset,omitempty"`
+	Collation     string `json:"collation,omitempty" yaml:"collation,omitempty"`
+	Comment       string `json:"comment,omitempty" yaml:"comment,omitempty"`
+	AutoIncrement string `json:"auto_increment,omitempty" yaml:"auto_increment,omitempty"`
+	RowFormat     string `json:"row_format,omitempty" yaml:"row_format,omitempty"`
 }
 
 type Table struct {
-	Name        string
-	Columns     []*Column
-	Constraints []*TableConstraint
+	Schema      string             `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Name        string             `json:"name,omitempty" yaml:"name,omitempty"`
+	Columns     []*Column          `json:"columns,omitempty" yaml:"columns,omitempty"`
+	Constraints []*TableConstraint `json:"constraints,omitempty" yaml:"constraints,omitempty"`
+	Options     *TableOptions      `json:"options,omitempty" yaml:"options,omitempty"`
+	Partition   *Partitioning      `json:"partition,omitempty" yaml:"partition,omitempty"`
+	Session     *Session           `json:"session,omitempty" yaml:"session,omitempty"` // nil unless changed by SET statement
+}
+
+// Partitioning is the PARTITION BY clause of table
+type Partitioning struct {
+	Type       string       `json:"type,omitempty" yaml:"type,omitempty"`             // HASH, KEY, RANGE, LIST, or the LINEAR HASH and LINEAR KEY
+	Expression string       `json:"expression,omitempty" yaml:"expression,omitempty"` // the expression of HASH, RANGE and LIST
+	Columns    []string     `json:"columns,omitempty" yaml:"columns,omitempty"`       // the columns of KEY, RANGE COLUMNS and LIST COLUMNS
+	Count      int          `json:"count,omitempty" yaml:"count,omitempty"`           // the number of PARTITIONS, 0 if not specified
+	Partitions []*Partition `json:"partitions,omitempty" yaml:"partitions,omitempty"`
+	Source     string       `json:"source,omitempty" yaml:"source,omitempty"` // source text of the clause, not changed by ADD or DROP PARTITION
+}
+
+// Partition is a partition definition like PARTITION p0 VALUES LESS THAN (10)
+type Partition struct {
+	Name   string `json:"name,omitempty" yaml:"name,omitempty"`
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
+}
+
+// Column return the column by name, nil if not exists.
+// Column names are case insensitive
+func (t *Table) Column(name string) *Column {
+	for _, col := range t.Columns {
+		if strings.EqualFold(col.Name, name) {
+			return col
+		}
+	}
+	return nil
 }
 
 func (t *Table) String() string {
@@ -25,49 +81,81 @@ func (t *Table) String() string {
 		str.WriteString("col ")
 		str.WriteString(col.Name)
 		str.WriteString("\t\t")
-		str.WriteString(col.DataType.Source)
+		if col.DataType != nil {
+			str.WriteString(col.DataType.Source)
+		}
 		str.WriteString("\t\t")
-		if col.Constraint.Comment != "" {
+		if col.Constraint != nil && col.Constraint.Comment != "" {
 			str.WriteString("comment ")
 			str.WriteString(col.Constraint.Comment)
 		}
 		str.WriteString("\n")
 	}
 	for _, cons := range t.Constraints {
-		if cons.ColumnPrimaryKey != nil {
+		switch {
+		case cons.ColumnPrimaryKey != nil:
 			str.WriteString("primary key: ")
 			str.WriteString(strings.Join(cons.ColumnPrimaryKey, ", "))
-			str.WriteString("\n")
-		}
-		if cons.ColumnUniqueKey != nil {
-			str.WriteString("primary key: ")
+		case cons.ColumnUniqueKey != nil:
+			str.WriteString("unique key ")
+			str.WriteString(cons.Name)
+			str.WriteString(": ")
 			str.WriteString(strings.Join(cons.ColumnUniqueKey, ", "))
-			str.WriteString("\n")
-		}
-		if cons.ColumnForeignKey != nil {
-			str.WriteString("primary key: ")
+		case cons.ColumnForeignKey != nil:
+			str.WriteString("foreign key ")
+			str.WriteString(cons.Name)
+			str.WriteString(": ")
 			str.WriteString(strings.Join(cons.ColumnForeignKey, ", "))
-			str.WriteString("\n")
+			if cons.Reference != nil && cons.Reference.Table != nil {
+				str.WriteString(" references ")
+				str.WriteString(cons.Reference.Table.String())
+				str.WriteString(" (")
+				str.WriteString(strings.Join(cons.Reference.Columns, ", "))
+				str.WriteString(")")
+			}
+		case cons.ColumnIndex != nil:
+			if cons.IndexKind != "" {
+				str.WriteString(strings.ToLower(cons.IndexKind))
+				str.WriteString(" ")
+			}
+			str.WriteString("index ")
+			str.WriteString(cons.Name)
+			str.WriteString(": ")
+			str.WriteString(strings.Join(cons.ColumnIndex, ", "))
+		case cons.Check != "":
+			str.WriteString("check ")
+			str.WriteString(cons.Name)
+			str.WriteString(": ")
+			str.WriteString(cons.Check)
+		default:
+			continue
 		}
+		str.WriteString("\n")
 	}
 	return str.String()
 }
 
 type Column struct {
-	Name       string
-	DataType   *DataType
-	Constraint *ColumnConstraint
+	Name       string            `json:"name,omitempty" yaml:"name,omitempty"`
+	DataType   *DataType         `json:"data_type,omitempty" yaml:"data_type,omitempty"`
+	Constraint *ColumnConstraint `json:"constraint,omitempty" yaml:"constraint,omitempty"`
 }
 
 type CreateTable struct {
+	IfNotExists bool
+	Schema      string // the qualified or current database, empty if unknown
 	Name        string
 	Columns     []*ColumnDeclaration
 	Constraints []*TableConstraint
+	Options     *TableOptions
+	Partition   *Partitioning
+	Session     *Session // the session in effect, nil unless changed by SET statement
 }
 
 // Convert from CreateTable to Table
 func (c *CreateTable) Convert() *Table {
 	var res Table
+	res.Schema = c.Schema
 	res.Name = onlyTableName(c.Name)
 	for _, col := range c.Columns {
 		def := col.ColumnDefinition
@@ -80,6 +168,9 @@ func (c *CreateTable) Convert() *Table {
 		res.Columns = append(res.Columns, &data)
 	}
 	res.Constraints = c.Constraints
+	res.Options = c.Options
+	res.Partition = c.Partition
+	res.Session = c.Session
 	return &res
 }
 
@@ -99,58 +190,541 @@ type ColumnDefinition struct {
 }
 
 type DataType struct {
-	Name   string
-	Number int
-	Source string
+	Name   string `json:"name,omitempty" yaml:"name,omitempty"`
+	Number int    `json:"-" yaml:"-"`
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
 
-	HasLength  bool
-	Length     int
-	IsNational bool
-	IsBinary   bool
-	IsNChar    bool
-	IsVarying  bool
+	HasLength  bool `json:"has_length,omitempty" yaml:"has_length,omitempty"`
+	Length     int  `json:"length,omitempty" yaml:"length,omitempty"`
+	IsNational bool `json:"is_national,omitempty" yaml:"is_national,omitempty"`
+	IsBinary   bool `json:"is_binary,omitempty" yaml:"is_binary,omitempty"`
+	IsNChar    bool `json:"is_nchar,omitempty" yaml:"is_nchar,omitempty"`
+	IsVarying  bool `json:"is_varying,omitempty" yaml:"is_varying,omitempty"`
 
-	IsSigned   bool
-	IsUnsigned bool
-	IsZeroFill bool
+	IsSigned   bool `json:"is_signed,omitempty" yaml:"is_signed,omitempty"`
+	IsUnsigned bool `json:"is_unsigned,omitempty" yaml:"is_unsigned,omitempty"`
+	IsZeroFill bool `json:"is_zero_fill,omitempty" yaml:"is_zero_fill,omitempty"`
 
 	// LengthTwoDimension | Optional
-	HasTwoLength bool
-	Len1         int
-	Len2         int
+	HasTwoLength bool `json:"has_two_length,omitempty" yaml:"has_two_length,omitempty"`
+	Len1         int  `json:"len1,omitempty" yaml:"len1,omitempty"`
+	Len2         int  `json:"len2,omitempty" yaml:"len2,omitempty"`
 
 	// Long
-	IsVarchar   bool
-	IsChar      bool
-	IsCharset   bool
-	IsCharacter bool
+	IsVarchar   bool `json:"is_varchar,omitempty" yaml:"is_varchar,omitempty"`
+	IsChar      bool `json:"is_char,omitempty" yaml:"is_char,omitempty"`
+	IsCharset   bool `json:"is_charset,omitempty" yaml:"is_charset,omitempty"`
+	IsCharacter bool `json:"is_character,omitempty" yaml:"is_character,omitempty"`
 
-	CharsetName string
+	CharsetName   string `json:"charset_name,omitempty" yaml:"charset_name,omitempty"`
+	CollationName string `json:"collation_name,omitempty" yaml:"collation_name,omitempty"` // by COLLATE of the data type or the column
 
-	CollectionOptions []string // for collectionDataType (enum, set)
+	CollectionOptions []string `json:"collection_options,omitempty" yaml:"collection_options,omitempty"` // for collectionDataType (enum, set)
 }
 
 type ColumnConstraint struct {
-	NotNull       bool
-	DefaultValue  *DefaultValue
-	AutoIncrement bool
-	Primary       bool
-	Key           bool
-	Unique        bool
-	Comment       string
+	NotNull       bool          `json:"not_null,omitempty" yaml:"not_null,omitempty"`
+	DefaultValue  *DefaultValue `json:"default_value,omitempty" yaml:"default_value,omitempty"`
+	AutoIncrement bool          `json:"auto_increment,omitempty" yaml:"auto_increment,omitempty"`
+	Primary       bool          `json:"primary,omitempty" yaml:"primary,omitempty"`
+	Key           bool          `json:"key,omitempty" yaml:"key,omitempty"`
+	Unique        bool          `json:"unique,omitempty" yaml:"unique,omitempty"`
+	Comment       string        `json:"comment,omitempty" yaml:"comment,omitempty"`
+	Generated     string        `json:"generated,omitempty" yaml:"generated,omitempty"` // the expression of generated column
+	Stored        bool          `json:"stored,omitempty" yaml:"stored,omitempty"`       // STORED generated column, VIRTUAL if false
+	OnUpdate      string        `json:"on_update,omitempty" yaml:"on_update,omitempty"` // ON UPDATE CURRENT_TIMESTAMP, e.g. CURRENT_TIMESTAMP(3)
 }
 
+// DefaultValue is the default of the column, Value is unquoted if Kind is DefaultString,
+// and the source otherwise, e.g. b'0', 0x0A or (uuid())
 type DefaultValue struct {
-	Value string
-	Is    bool
+	Value string `json:"value,omitempty" yaml:"value,omitempty"`
+	Is    bool   `json:"is,omitempty" yaml:"is,omitempty"`
+	Kind  string `json:"kind,omitempty" yaml:"kind,omitempty"`
 }
 
+// the kinds of DefaultValue, empty if unknown like the snapshots of the older versions
+const (
+	DefaultString     = "string"
+	DefaultNumber     = "number"
+	DefaultBit        = "bit"
+	DefaultHex        = "hex"
+	DefaultExpression = "expression" // the functions, keywords and expressions, e.g. CURRENT_TIMESTAMP
+)
+
 type key bool
 type primary bool
 
 func onlyTableName(name string) string {
 	// antlr4 parse table name `db_name`.`tbl_name` as string
-	// "db_name`.`tbl_name"
+	// "db_name`.`tbl_name", and db_name.tbl_name as "db_name.tbl_name"
 	ss := strings.Split(name, "`.`")
+	if len(ss) == 1 && !strings.Contains(name, "`") {
+		ss = strings.Split(name, ".")
+	}
 	return ss[len(ss)-1]
 }
+
+// TableName is a table name which may be qualified by a schema (database) name
+type TableName struct {
+	Schema string `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Name   string `json:"name,omitempty" yaml:"name,omitempty"`
+}
+
+func (t *TableName) String() string {
+	if t.Schema == "" {
+		return t.Name
+	}
+	return t.Schema + "." + t.Name
+}
+
+type DropTable struct {
+	Temporary bool
+	IfExists  bool
+	Tables    []*TableName
+	Restrict  bool
+	Cascade   bool
+}
+
+type RenameTable struct {
+	Clauses []*RenameTableClause
+}
+
+type RenameTableClause struct {
+	From *TableName
+	To   *TableName
+}
+
+type TruncateTable struct {
+	Table *TableName
+}
+
+// The actions of ALTER TABLE specification
+const (
+	AlterAddColumn       = "ADD COLUMN"
+	AlterChangeColumn    = "CHANGE COLUMN"
+	AlterModifyColumn    = "MODIFY COLUMN"
+	AlterRenameColumn    = "RENAME COLUMN"
+	AlterDropColumn      = "DROP COLUMN"
+	AlterSetDefault      = "SET DEFAULT"
+	AlterDropDefault     = "DROP DEFAULT"
+	AlterAddConstraint   = "ADD CONSTRAINT" // primary key, unique key, index, foreign key or check
+	AlterDropPrimaryKey  = "DROP PRIMARY KEY"
+	AlterDropIndex       = "DROP INDEX"
+	AlterDropForeignKey  = "DROP FOREIGN KEY"
+	AlterDropCheck       = "DROP CHECK"
+	AlterDropConstraint  = "DROP CONSTRAINT"
+	AlterRenameIndex     = "RENAME INDEX"
+	AlterIndexVisibility = "ALTER INDEX"
+	AlterPartitionBy     = "PARTITION BY"
+	AlterAddPartition    = "ADD PARTITION"
+	AlterDropPartition   = "DROP PARTITION"
+	AlterRemovePartition = "REMOVE PARTITIONING"
+	AlterRenameTable     = "RENAME TABLE"
+	AlterTableOptions    = "TABLE OPTIONS"
+	AlterConvertCharset  = "CONVERT TO CHARACTER SET"
+	AlterAlgorithm       = "ALGORITHM"
+	AlterLock            = "LOCK"
+	AlterOther           = "OTHER" // the specification not modeled, see Source
+)
+
+type AlterTable struct {
+	Table *TableName
+	Specs []*AlterSpecification
+}
+
+// AlterSpecification is one change of ALTER TABLE, the fields used depend on the Action
+type AlterSpecification struct {
+	Action string
+	Source string // source text of the specification
+
+	Name    string  // the column, index, constraint or partition to change
+	NewName string  // the new name of CHANGE COLUMN, RENAME COLUMN and RENAME INDEX
+	Column  *Column // the new definition of ADD, CHANGE and MODIFY COLUMN
+	First   bool    // FIRST of ADD, CHANGE and MODIFY COLUMN
+	After   string  // AFTER of ADD, CHANGE and MODIFY COLUMN
+
+	Default    *DefaultValue    // SET DEFAULT
+	Constraint *TableConstraint // ADD CONSTRAINT
+	Options    *TableOptions    // TABLE OPTIONS and CONVERT TO CHARACTER SET
+	RenameTo   *TableName       // RENAME TABLE
+	Partition  *Partitioning    // PARTITION BY, the partitions of ADD PARTITION
+	Value      string           // ALGORITHM, LOCK and the VISIBLE or INVISIBLE of ALTER INDEX
+}
+
+// CreateIndex is CREATE INDEX, the Constraint is a named unique key or index
+type CreateIndex struct {
+	Table      *TableName
+	Constraint *TableConstraint
+	Algorithm  string
+	Lock       string
+}
+
+type DropIndex struct {
+	Name      string
+	Table     *TableName
+	Algorithm string
+	Lock      string
+}
+
+// Database is the schema level options
+type Database struct {
+	Name       string `json:"name,omitempty" yaml:"name,omitempty"`
+	Charset    string `json:"charset,omitempty" yaml:"charset,omitempty"`
+	Collation  string `json:"collation,omitempty" yaml:"collation,omitempty"`
+	Encryption string `json:"encryption,omitempty" yaml:"encryption,omitempty"`
+	ReadOnly   string `json:"read_only,omitempty" yaml:"read_only,omitempty"` // DEFAULT, 0 or 1
+}
+
+type CreateDatabase struct {
+	IfNotExists bool
+	Database    *Database
+}
+
+// AlterDatabase use the current database if the name is omitted
+type AlterDatabase struct {
+	Database *Database
+}
+
+type DropDatabase struct {
+	IfExists bool
+	Name     string
+}
+
+type UseDatabase struct {
+	Name string
+}
+
+type View struct {
+	Name        *TableName `json:"name,omitempty" yaml:"name,omitempty"`
+	Algorithm   string     `json:"algorithm,omitempty" yaml:"algorithm,omitempty"` // UNDEFINED, MERGE or TEMPTABLE
+	Definer     string     `json:"definer,omitempty" yaml:"definer,omitempty"`
+	SQLSecurity string     `json:"sql_security,omitempty" yaml:"sql_security,omitempty"` // DEFINER or INVOKER
+	Columns     []string   `json:"columns,omitempty" yaml:"columns,omitempty"`
+	Select      string     `json:"select,omitempty" yaml:"select,omitempty"` // source text of the select statement
+
+	WithCheckOption bool   `json:"with_check_option,omitempty" yaml:"with_check_option,omitempty"`
+	CheckOption     string `json:"check_option,omitempty" yaml:"check_option,omitempty"` // CASCADED or LOCAL, empty if not specified
+
+	// Tables the select statement references, common table expressions excluded
+	Tables []*TableName `json:"tables,omitempty" yaml:"tables,omitempty"`
+}
+
+type CreateView struct {
+	OrReplace bool
+	View      *View
+}
+
+type AlterView struct {
+	View *View
+}
+
+type DropView struct {
+	IfExists bool
+	Views    []*TableName
+	Restrict bool
+	Cascade  bool
+}
+
+// Statement is a parsed statement with the location in the input
+type Statement struct {
+	File   string // the file name, empty if parsed from string
+	Span   Span
+	Source string      // source text of the statement
+	Node   interface{} // the statement like *CreateTable, *AlterTable
+}
+
+// Span is the location of a source text in the input
+type Span struct {
+	Start  int // character offset of the first character
+	Stop   int // character offset of the last character
+	Line   int // line of the first character, start from 1
+	Column int // column of the first character, start from 0
+}
+
+// RoutineBody is the body of trigger, stored routine and event
+type RoutineBody struct {
+	Span       Span
+	Source     string   // source text of the whole body
+	Statements []string // source text of the top level statements, without the ending semicolon
+
+	Tables []*TableName // tables read or written
+	Writes []*TableName // tables written by INSERT, REPLACE, UPDATE or DELETE
+}
+
+type Trigger struct {
+	Name    *TableName
+	Definer string
+	Time    string // BEFORE or AFTER
+	Event   string // INSERT, UPDATE or DELETE
+	Table   *TableName
+
+	Order        string // FOLLOWS or PRECEDES, empty if not specified
+	OtherTrigger *TableName
+
+	Body *RoutineBody
+}
+
+type CreateTrigger struct {
+	Trigger *Trigger
+}
+
+type DropTrigger struct {
+	IfExists bool
+	Name     *TableName
+}
+
+type RoutineParameter struct {
+	Direction string // IN, OUT or INOUT, empty for function and the default IN
+	Name      string
+	DataType  *DataType
+}
+
+// RoutineCharacteristics is the routineOption of stored procedure and function
+type RoutineCharacteristics struct {
+	Comment       string
+	Language      string // SQL
+	Deterministic bool
+	DataAccess    string // CONTAINS SQL, NO SQL, READS SQL DATA or MODIFIES SQL DATA
+	SQLSecurity   string // DEFINER or INVOKER
+}
+
+type Procedure struct {
+	Name            *TableName
+	Definer         string
+	Parameters      []*RoutineParameter
+	Characteristics *RoutineCharacteristics
+	Body            *RoutineBody
+}
+
+type Function struct {
+	Name            *TableName
+	Definer         string
+	Aggregate       bool
+	Parameters      []*RoutineParameter
+	Returns         *DataType
+	Characteristics *RoutineCharacteristics
+	Body            *RoutineBody
+}
+
+type CreateProcedure struct {
+	Procedure *Procedure
+}
+
+type CreateFunction struct {
+	IfNotExists bool
+	Function    *Function
+}
+
+type DropProcedure struct {
+	IfExists bool
+	Name     *TableName
+}
+
+type DropFunction struct {
+	IfExists bool
+	Name     *TableName
+}
+
+// Interval is like INTERVAL 1 DAY, the value is a number, a quoted string like '1:30'
+// with the quotes trimmed, or an expression text
+type Interval struct {
+	Value string
+	Unit  string // DAY, HOUR_MINUTE etc.
+}
+
+// EventTimestamp is the timestampValue with the following intervals, like
+// CURRENT_TIMESTAMP + INTERVAL 1 DAY
+type EventTimestamp struct {
+	Value     string    // CURRENT_TIMESTAMP, the literal with quotes trimmed or an expression text
+	Time      time.Time // zero unless the value is a datetime literal
+	Intervals []*Interval
+}
+
+// Schedule is the scheduleExpression, either At or Every is set
+type Schedule struct {
+	At *EventTimestamp
+
+	Every  *Interval
+	Starts *EventTimestamp
+	Ends   *EventTimestamp
+}
+
+type Event struct {
+	Name     *TableName
+	Definer  string
+	Schedule *Schedule
+
+	OnCompletion string // PRESERVE or NOT PRESERVE, empty if not specified
+	Status       string // ENABLE, DISABLE or DISABLE ON SLAVE, empty if not specified
+	Comment      string
+	Body         *RoutineBody
+}
+
+type CreateEvent struct {
+	IfNotExists bool
+	Event       *Event
+}
+
+// AlterEvent only carry the changed fields of the event
+type AlterEvent struct {
+	Event    *Event
+	RenameTo *TableName
+}
+
+type DropEvent struct {
+	IfExists bool
+	Name     *TableName
+}
+
+// Principal is a user account or a role, the host is empty for a role without host
+type Principal struct {
+	User string
+	Host string
+}
+
+func (p *Principal) String() string {
+	if p.Host == "" {
+		return p.User
+	}
+	return p.User + "@" + p.Host
+}
+
+// privilege scope
+const (
+	ScopeGlobal   = "GLOBAL"   // ON *.*
+	ScopeDatabase = "DATABASE" // ON db.*
+	ScopeTable    = "TABLE"    // ON db.tbl, the privilege with columns is column level
+	ScopeRoutine  = "ROUTINE"  // ON FUNCTION db.fn or ON PROCEDURE db.proc
+)
+
+// Privilege is like SELECT or SELECT (col1, col2)
+type Privilege struct {
+	Type    string // SELECT, ALL PRIVILEGES, CREATE TEMPORARY TABLES etc.
+	Columns []string
+}
+
+// PrivilegeObject is the privilegeLevel of GRANT and REVOKE
+type PrivilegeObject struct {
+	Scope      string
+	ObjectType string // TABLE, FUNCTION or PROCEDURE if specified
+	Schema     string // empty for global scope
+	Name       string // empty for global and database scope
+}
+
+type CreateUser struct {
+	IfNotExists  bool
+	Users        []*Principal
+	DefaultRoles []*Principal
+}
+
+type DropUser struct {
+	IfExists bool
+	Users    []*Principal
+}
+
+type CreateRole struct {
+	IfNotExists bool
+	Roles       []*Principal
+}
+
+type DropRole struct {
+	IfExists bool
+	Roles    []*Principal
+}
+
+// Grant is GRANT privileges ON object TO users
+type Grant struct {
+	Privileges      []*Privilege
+	Object          *PrivilegeObject
+	To              []*Principal
+	WithGrantOption bool
+}
+
+// GrantRole is GRANT roles TO users
+type GrantRole struct {
+	Roles           []*Principal
+	To              []*Principal
+	WithAdminOption bool
+}
+
+// Revoke is REVOKE privileges ON object FROM users, the object is nil for
+// REVOKE ALL PRIVILEGES, GRANT OPTION FROM users
+type Revoke struct {
+	Privileges []*Privilege
+	Object     *PrivilegeObject
+	From       []*Principal
+}
+
+// RevokeRole is REVOKE roles FROM users
+type RevokeRole struct {
+	Roles []*Principal
+	From  []*Principal
+}
+
+// SetRole is SET ROLE or SET DEFAULT ROLE ... TO users
+type SetRole struct {
+	Default bool
+	Option  string // DEFAULT, NONE, ALL, empty if the roles are listed
+	Roles   []*Principal
+	Except  []*Principal // ALL EXCEPT roles
+	To      []*Principal
+}
+
+// variable scope of SET statement
+const (
+	VariableUser    = "USER"    // @var
+	VariableSession = "SESSION" // var, @@var, @@SESSION.var, SESSION var and LOCAL var
+	VariableGlobal  = "GLOBAL"  // @@GLOBAL.var and GLOBAL var
+)
+
+type VariableAssignment struct {
+	Scope string
+	Name  string
+	Value string // source text of the value expression, like 'TRADITIONAL' or @@SQL_MODE
+}
+
+type SetVariable struct {
+	Assignments []*VariableAssignment
+}
+
+// SetNames is SET NAMES charset COLLATE collation, the charset is DEFAULT for SET NAMES DEFAULT
+type SetNames struct {
+	Charset   string
+	Collation string
+}
+
+// SetCharset is SET CHARACTER SET charset
+type SetCharset struct {
+	Charset string
+}
+
+// Session is the session variables which change the meaning of the following statements,
+// the client charset is of the literals, the tables take the database or server charset
+type Session struct {
+	SQLMode              string `json:"sql_mode,omitempty" yaml:"sql_mode,omitempty"`
+	CharacterSetClient   string `json:"character_set_client,omitempty" yaml:"character_set_client,omitempty"`
+	CollationConnection  string `json:"collation_connection,omitempty" yaml:"collation_connection,omitempty"`
+	CharacterSetDatabase string `json:"character_set_database,omitempty" yaml:"character_set_database,omitempty"`
+	CollationDatabase    string `json:"collation_database,omitempty" yaml:"collation_database,omitempty"`
+	CharacterSetServer   string `json:"character_set_server,omitempty" yaml:"character_set_server,omitempty"`
+	CollationServer      string `json:"collation_server,omitempty" yaml:"collation_server,omitempty"`
+}
+
+// HasMode report whether the sql_mode includes mode
+func (s *Session) HasMode(mode string) bool {
+	if s == nil {
+		return false
+	}
+	for _, m := range strings.Split(s.SQLMode, ",") {
+		if strings.EqualFold(strings.TrimSpace(m), mode) {
+			return true
+		}
+	}
+	return false
+}
+
+// ANSIQuotes report whether double quote is identifier quote, the ANSI mode include ANSI_QUOTES
+func (s *Session) ANSIQuotes() bool {
+	return s.HasMode("ANSI_QUOTES") || s.HasMode("ANSI")
+}
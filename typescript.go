@@ -0,0 +1,118 @@
+package sqlparser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// TypeScriptOptions is the options of GenerateTypeScript, the zero value is usable
+type TypeScriptOptions struct {
+	// BigInt is the type of BIGINT, string if empty, or bigint or number.
+	// The string keeps the values over Number.MAX_SAFE_INTEGER parsed by JSON.parse
+	BigInt string
+}
+
+// GenerateTypeScript generate the .d.ts of one interface per table. The ENUM columns are the
+// unions of string literals, the SET columns are the arrays of the unions, the nullable columns
+// are | null and the comments are JSDoc
+func GenerateTypeScript(tables []*Table, opts *TypeScriptOptions) []byte {
+	var o TypeScriptOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.BigInt == "" {
+		o.BigInt = "string"
+	}
+
+	var str strings.Builder
+	str.WriteString("// Code generated by sqlparser. DO NOT EDIT.\n")
+	for _, t := range tables {
+		name := pascalCase(t.Name, false)
+		var unions strings.Builder
+		str.WriteString("\n")
+		writeJSDoc(&str, "", tableComment(t))
+		str.WriteString("export interface " + name + " {\n")
+		for _, col := range t.Columns {
+			if col.Constraint != nil {
+				writeJSDoc(&str, "  ", col.Constraint.Comment)
+			}
+			typ := "string"
+			if dt := col.DataType; dt != nil && (dt.Name == "ENUM" || dt.Name == "SET") {
+				typ = name + pascalCase(col.Name, false)
+				unions.WriteString(fmt.Sprintf("\n/** The values of %s.%s */\n", t.Name, col.Name))
+				unions.WriteString(fmt.Sprintf("export type %s = %s;\n", typ, typeScriptUnion(dt.CollectionOptions)))
+				if dt.Name == "SET" {
+					typ += "[]"
+				}
+			} else if dt != nil {
+				typ = typeScriptType(dt, o.BigInt)
+			}
+			if columnNullable(t, col) {
+				typ += " | null"
+			}
+			str.WriteString(fmt.Sprintf("  %s: %s;\n", typeScriptProperty(col.Name), typ))
+		}
+		str.WriteString("}\n")
+		str.WriteString(unions.String())
+	}
+	return []byte(str.String())
+}
+
+func typeScriptType(dt *DataType, bigint string) string {
+	if dt.Name == "BOOL" || dt.Name == "BOOLEAN" {
+		return "boolean"
+	}
+	name := foreignKeyType(dt)
+	switch {
+	case name == "BIGINT":
+		return bigint
+	case isIntegerType(name), name == "FLOAT", name == "DOUBLE", name == "YEAR":
+		return "number"
+	case name == "JSON":
+		return "unknown"
+	}
+	// DECIMAL is string to keep the precision, the date and time are the strings of JSON,
+	// and the binary types are base64
+	return "string"
+}
+
+// typeScriptUnion return the union of the string literals, never if no value
+func typeScriptUnion(values []string) string {
+	if len(values) == 0 {
+		return "never"
+	}
+	var literals []string
+	for _, value := range values {
+		literals = append(literals, "'"+strings.NewReplacer(`\`, `\\`, "'", `\'`, "\n", `\n`, "\r", `\r`).Replace(value)+"'")
+	}
+	return strings.Join(literals, " | ")
+}
+
+var typeScriptIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// typeScriptProperty return the name, quoted if not an identifier
+func typeScriptProperty(name string) string {
+	if typeScriptIdentifier.MatchString(name) {
+		return name
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(name) + "'"
+}
+
+// writeJSDoc write the comment in one line, or in lines if multiple lines
+func writeJSDoc(str *strings.Builder, indent, comment string) {
+	if comment == "" {
+		return
+	}
+	comment = strings.ReplaceAll(comment, "*/", `*\/`)
+	lines := strings.Split(comment, "\n")
+	if len(lines) == 1 {
+		str.WriteString(indent + "/** " + comment + " */\n")
+		return
+	}
+	str.WriteString(indent + "/**\n")
+	for _, line := range lines {
+		str.WriteString(strings.TrimRight(indent+" * "+line, " ") + "\n")
+	}
+	str.WriteString(indent + " */\n")
+}
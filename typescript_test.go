@@ -0,0 +1,55 @@
+package sqlparser
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGenerateTypeScript(t *testing.T) {
+	Convey("TestGenerateTypeScript", t, func() {
+		tables, err := From(`CREATE TABLE user_account (
+  id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  age tinyint NOT NULL,
+  name varchar(64) NOT NULL COMMENT '用户名，不能包含 */',
+  status enum('active','it''s') NOT NULL,
+  tags set('a','b'),
+  balance decimal(10,2),
+  is_admin bool NOT NULL,
+  created_at datetime NOT NULL,
+  extra json,
+  ` + "`first-name`" + ` varchar(10)
+) COMMENT '用户表';`)
+		So(err, ShouldBeNil)
+
+		Convey("Default", func() {
+			So(string(GenerateTypeScript(tables, nil)), ShouldEqual, `// Code generated by sqlparser. DO NOT EDIT.
+
+/** 用户表 */
+export interface UserAccount {
+  id: string;
+  age: number;
+  /** 用户名，不能包含 *\/ */
+  name: string;
+  status: UserAccountStatus;
+  tags: UserAccountTags[] | null;
+  balance: string | null;
+  is_admin: boolean;
+  created_at: string;
+  extra: unknown | null;
+  'first-name': string | null;
+}
+
+/** The values of user_account.status */
+export type UserAccountStatus = 'active' | 'it\'s';
+
+/** The values of user_account.tags */
+export type UserAccountTags = 'a' | 'b';
+`)
+		})
+
+		Convey("BigInt", func() {
+			So(string(GenerateTypeScript(tables, &TypeScriptOptions{BigInt: "bigint"})), ShouldContainSubstring, "  id: bigint;\n")
+		})
+	})
+}
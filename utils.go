@@ -1,8 +1,11 @@
 package sqlparser
 
 import (
+	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"unicode"
 )
 
 func FileExists(name string) bool {
@@ -21,3 +24,170 @@ func WithReplacer(str string, oldnew ...string) string {
 func WithTrimBracket(str string) string {
 	return strings.Trim(str, "([{}])")
 }
+
+// WithExecutableComments unwrap the MySQL executable comments like /*!40101 SET NAMES utf8 */
+// which mysqldump writes, the comments of a version newer than the target version like 8.0.12 are kept,
+// empty version for the latest. The quoted strings and the other comments are not changed, and
+// the offsets are kept by replacing the markers with spaces
+func WithExecutableComments(str, version string) string {
+	b := []byte(str)
+	executed := false
+	for i := 0; i < len(b); {
+		switch c := b[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			i = skipQuoted(str, i)
+		case c == '#' || strings.HasPrefix(str[i:], "--") && (i+2 == len(b) || unicode.IsSpace(rune(b[i+2]))):
+			if end := strings.IndexByte(str[i:], '\n'); end != -1 {
+				i += end
+			} else {
+				i = len(b)
+			}
+		case executed && strings.HasPrefix(str[i:], "*/"):
+			b[i], b[i+1] = ' ', ' '
+			executed = false
+			i += 2
+		case strings.HasPrefix(str[i:], "/*!"):
+			j := i + len("/*!")
+			for j < len(b) && j-i-3 < 6 && b[j] >= '0' && b[j] <= '9' {
+				j++
+			}
+			if len(str[i+3:j]) != 5 && len(str[i+3:j]) != 6 {
+				j = i + len("/*!")
+			}
+			if !executableVersion(str[i+3:j], version) {
+				i = skipComment(str, i)
+				break
+			}
+			for k := i; k < j; k++ {
+				b[k] = ' '
+			}
+			executed = true
+			i = j
+		case strings.HasPrefix(str[i:], "/*"):
+			i = skipComment(str, i)
+		default:
+			i++
+		}
+	}
+	return string(b)
+}
+
+// skipQuoted return the offset after the string or identifier quoted at i
+func skipQuoted(str string, i int) int {
+	quote := str[i]
+	for j := i + 1; j < len(str); j++ {
+		switch {
+		case str[j] == '\\' && quote != '`':
+			j++
+		case str[j] == quote:
+			return j + 1
+		}
+	}
+	return len(str)
+}
+
+// skipComment return the offset after the comment started at i
+func skipComment(str string, i int) int {
+	if end := strings.Index(str[i+2:], "*/"); end != -1 {
+		return i + 2 + end + len("*/")
+	}
+	return len(str)
+}
+
+// executableVersion return whether the executable comment of the version like 80030 is executed by the target version
+func executableVersion(digits, version string) bool {
+	if digits == "" || version == "" {
+		return true
+	}
+	n, _ := strconv.Atoi(digits)
+	return compareVersion(fmt.Sprintf("%d.%d.%d", n/10000, n/100%100, n%100), version) <= 0
+}
+
+// WithUnquote remove the quotes of a string literal and unescape the doubled quotes
+// and backslash escapes like \n, the str is returned as is if not quoted
+func WithUnquote(str string) string {
+	if len(str) < 2 || (str[0] != '\'' && str[0] != '"') || str[len(str)-1] != str[0] {
+		return str
+	}
+	quote := string(str[0])
+	str = strings.ReplaceAll(str[1:len(str)-1], quote+quote, quote)
+	return strings.NewReplacer(`\\`, `\`, `\'`, `'`, `\"`, `"`, `\n`, "\n", `\r`, "\r", `\t`, "\t", `\0`, "\x00").Replace(str)
+}
+
+// splitWords split the identifier like user_id, userId or HTTPServer into words
+func splitWords(str string) []string {
+	var res []string
+	var word []rune
+	runes := []rune(str)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(word) != 0 {
+				res = append(res, string(word))
+				word = nil
+			}
+			continue
+		}
+		if len(word) != 0 && unicode.IsUpper(r) {
+			prev := word[len(word)-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				res = append(res, string(word))
+				word = nil
+			}
+		}
+		word = append(word, r)
+	}
+	if len(word) != 0 {
+		res = append(res, string(word))
+	}
+	return res
+}
+
+// commonInitialisms is the words written in upper case in Go names, like golint
+var commonInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true, "GUID": true,
+	"HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true, "LHS": true, "QPS": true,
+	"RAM": true, "RHS": true, "RPC": true, "SLA": true, "SMTP": true, "SQL": true, "SSH": true, "TCP": true,
+	"TLS": true, "TTL": true, "UDP": true, "UI": true, "UID": true, "UUID": true, "URI": true, "URL": true,
+	"UTF8": true, "VM": true, "XML": true, "XMPP": true, "XSRF": true, "XSS": true,
+}
+
+// pascalCase join the words capitalized, the common initialisms are upper case if initialisms.
+// The name starting with a digit is prefixed by X to be an identifier
+func pascalCase(str string, initialisms bool) string {
+	res := pascalWords(str, initialisms)
+	if res != "" && unicode.IsDigit([]rune(res)[0]) {
+		return "X" + res
+	}
+	return res
+}
+
+// pascalWords is pascalCase without the prefix, for the suffix of a name
+func pascalWords(str string, initialisms bool) string {
+	var res strings.Builder
+	for _, word := range splitWords(str) {
+		upper := strings.ToUpper(word)
+		if initialisms && commonInitialisms[upper] {
+			res.WriteString(upper)
+			continue
+		}
+		runes := []rune(strings.ToLower(word))
+		runes[0] = unicode.ToUpper(runes[0])
+		res.WriteString(string(runes))
+	}
+	return res.String()
+}
+
+// camelCase is pascalCase with the first word in lower case
+func camelCase(str string) string {
+	words := splitWords(str)
+	if len(words) == 0 {
+		return ""
+	}
+	return strings.ToLower(words[0]) + pascalCase(strings.Join(words[1:], "_"), false)
+}
+
+// snakeCase join the words in lower case with underscores
+func snakeCase(str string) string {
+	return strings.ToLower(strings.Join(splitWords(str), "_"))
+}
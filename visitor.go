@@ -4,111 +4,1536 @@ import (
 	"log/slog"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/antlr4-go/antlr/v4"
 )
 
 type Visitor struct {
 	*BaseMySqlParserVisitor
+
+	// database is the current database changed by USE statement
+	database string
+	// session is changed by SET statement, replaced rather than modified
+	// so the statements can keep the pointer
+	session *Session
+	// userVariables keep the resolved value of @var, like @OLD_SQL_MODE=@@SQL_MODE
+	userVariables map[string]string
+}
+
+var _ MySqlParserVisitor = (*Visitor)(nil)
+
+func (v *Visitor) VisitUid(ctx *UidContext) interface{} {
+	str := v.identifier(ctx.GetText())
+	str = WithReplacer(str, "\r", "", "\n", "")
+	return str
+}
+
+// identifier remove the quotes of the quoted identifier and unescape the doubled quotes.
+// The double quote is identifier quote if the sql_mode include ANSI_QUOTES,
+// otherwise the quotes of a string used as identifier are trimmed
+func (v *Visitor) identifier(str string) string {
+	if len(str) >= 2 && str[len(str)-1] == str[0] && (str[0] == '`' || str[0] == '"' && v.session.ANSIQuotes()) {
+		quote := string(str[0])
+		return strings.ReplaceAll(str[1:len(str)-1], quote+quote, quote)
+	}
+	return WithTrimQuote(str)
+}
+
+func (v *Visitor) VisitIndexColumnName(ctx *IndexColumnNameContext) interface{} {
+	var col string
+	if ctx.Uid() != nil {
+		uidCtx := ctx.Uid().(*UidContext)
+		tmp := v.VisitUid(uidCtx)
+		if val, ok := tmp.(string); ok {
+			col = val
+		}
+	} else if ctx.STRING_LITERAL() != nil {
+		col = v.identifier(ctx.STRING_LITERAL().GetText())
+		col = WithReplacer(col, "\t", "", "\n", "", "\r", "")
+	} else if expr := ctx.Expression(); expr != nil {
+		// functional key part, keep the parenthesized expression
+		col = sourceText(expr.GetStart(), expr.GetStop())
+	}
+	slog.Debug("VisitIndexColumnName", "index column name", col)
+	return col
+}
+
+// visitUid return the name of ctx, empty if ctx is nil
+func (v *Visitor) visitUid(ctx IUidContext) string {
+	if uu, ok := ctx.(*UidContext); ok {
+		if name, ok := v.VisitUid(uu).(string); ok {
+			return name
+		}
+	}
+	return ""
+}
+
+func (v *Visitor) VisitUidList(ctx *UidListContext) interface{} {
+	var res []string
+	for _, uid := range ctx.AllUid() {
+		if uu, ok := uid.(*UidContext); ok {
+			if name, ok := v.VisitUid(uu).(string); ok {
+				res = append(res, name)
+			}
+		}
+	}
+	return res
+}
+
+func (v *Visitor) VisitIndexColumnNames(ctx *IndexColumnNamesContext) interface{} {
+	var cols []string
+	for _, col := range ctx.AllIndexColumnName() {
+		if indexCtx, ok := col.(*IndexColumnNameContext); ok {
+			indexColName := v.VisitIndexColumnName(indexCtx)
+			if val, ok := indexColName.(string); ok {
+				cols = append(cols, val)
+			}
+		}
+	}
+	slog.Debug("VisitIndexColumnNames", "index column names", cols)
+	return cols
+}
+
+// sqlstatement start
+
+func (v *Visitor) VisitRoot(ctx *RootContext) interface{} {
+	if val, ok := ctx.SqlStatements().(*SqlStatementsContext); ok && val != nil {
+		return v.VisitSqlStatements(val)
+	}
+	return nil
+}
+
+// VisitSqlStatements return the []*CreateTable in order, the other statements
+// are returned by visitStatements only
+func (v *Visitor) VisitSqlStatements(ctx *SqlStatementsContext) interface{} {
+	var createTables []*CreateTable
+	for _, stmt := range v.visitStatements(ctx) {
+		if data, ok := stmt.Node.(*CreateTable); ok {
+			createTables = append(createTables, data)
+		}
+	}
+	return createTables
+}
+
+// visitStatements return every supported statement in order with the location
+func (v *Visitor) visitStatements(ctx *SqlStatementsContext) []*Statement {
+	var statements []*Statement
+	for _, val := range ctx.AllSqlStatement() {
+		if sqlStatementCtx, ok := val.(*SqlStatementContext); ok && sqlStatementCtx != nil {
+			res := v.VisitSqlStatement(sqlStatementCtx)
+			if res != nil {
+				statements = append(statements, &Statement{
+					Span:   sourceSpan(sqlStatementCtx.GetStart(), sqlStatementCtx.GetStop()),
+					Source: sourceText(sqlStatementCtx.GetStart(), sqlStatementCtx.GetStop()),
+					Node:   res,
+				})
+			}
+		}
+	}
+	return statements
+}
+
+func (v *Visitor) VisitSqlStatement(ctx *SqlStatementContext) interface{} {
+	if tmp := ctx.DdlStatement(); tmp != nil {
+		return v.VisitDdlStatement(tmp.(*DdlStatementContext))
+	}
+	if tmp := ctx.DmlStatement(); tmp != nil {
+		slog.Warn("unsupport VisitDmlStatement")
+	}
+	if tmp := ctx.TransactionStatement(); tmp != nil {
+		slog.Warn("unsupport VisitTransactionStatement")
+	}
+	if tmp := ctx.ReplicationStatement(); tmp != nil {
+		slog.Warn("unsupport VisitReplicationStatement")
+	}
+	if tmp := ctx.PreparedStatement(); tmp != nil {
+		slog.Warn("unsupport VisitPreparedStatement")
+	}
+	if tmp, ok := ctx.AdministrationStatement().(*AdministrationStatementContext); ok {
+		return v.VisitAdministrationStatement(tmp)
+	}
+	if tmp, ok := ctx.UtilityStatement().(*UtilityStatementContext); ok {
+		return v.VisitUtilityStatement(tmp)
+	}
+	return nil
+}
+
+func (v *Visitor) VisitDdlStatement(ctx *DdlStatementContext) interface{} {
+	if ctx.CreateTable() != nil {
+		return v.VisitCreateTable(ctx.CreateTable())
+	}
+	if tmp, ok := ctx.CreateDatabase().(*CreateDatabaseContext); ok {
+		return v.VisitCreateDatabase(tmp)
+	}
+	if tmp, ok := ctx.AlterDatabase().(*AlterSimpleDatabaseContext); ok {
+		return v.VisitAlterSimpleDatabase(tmp)
+	}
+	if tmp, ok := ctx.DropDatabase().(*DropDatabaseContext); ok {
+		return v.VisitDropDatabase(tmp)
+	}
+	if tmp, ok := ctx.CreateView().(*CreateViewContext); ok {
+		return v.VisitCreateView(tmp)
+	}
+	if tmp, ok := ctx.AlterView().(*AlterViewContext); ok {
+		return v.VisitAlterView(tmp)
+	}
+	if tmp, ok := ctx.DropView().(*DropViewContext); ok {
+		return v.VisitDropView(tmp)
+	}
+	if tmp, ok := ctx.CreateTrigger().(*CreateTriggerContext); ok {
+		return v.VisitCreateTrigger(tmp)
+	}
+	if tmp, ok := ctx.DropTrigger().(*DropTriggerContext); ok {
+		return v.VisitDropTrigger(tmp)
+	}
+	if tmp, ok := ctx.CreateProcedure().(*CreateProcedureContext); ok {
+		return v.VisitCreateProcedure(tmp)
+	}
+	if tmp, ok := ctx.CreateFunction().(*CreateFunctionContext); ok {
+		return v.VisitCreateFunction(tmp)
+	}
+	if tmp, ok := ctx.DropProcedure().(*DropProcedureContext); ok {
+		return v.VisitDropProcedure(tmp)
+	}
+	if tmp, ok := ctx.DropFunction().(*DropFunctionContext); ok {
+		return v.VisitDropFunction(tmp)
+	}
+	if tmp, ok := ctx.CreateEvent().(*CreateEventContext); ok {
+		return v.VisitCreateEvent(tmp)
+	}
+	if tmp, ok := ctx.AlterEvent().(*AlterEventContext); ok {
+		return v.VisitAlterEvent(tmp)
+	}
+	if tmp, ok := ctx.DropEvent().(*DropEventContext); ok {
+		return v.VisitDropEvent(tmp)
+	}
+	if tmp, ok := ctx.CreateRole().(*CreateRoleContext); ok {
+		return v.VisitCreateRole(tmp)
+	}
+	if tmp, ok := ctx.DropRole().(*DropRoleContext); ok {
+		return v.VisitDropRole(tmp)
+	}
+	if tmp, ok := ctx.SetRole().(*SetRoleContext); ok {
+		return v.VisitSetRole(tmp)
+	}
+	if tmp, ok := ctx.AlterTable().(*AlterTableContext); ok {
+		return v.VisitAlterTable(tmp)
+	}
+	if tmp, ok := ctx.CreateIndex().(*CreateIndexContext); ok {
+		return v.VisitCreateIndex(tmp)
+	}
+	if tmp, ok := ctx.DropIndex().(*DropIndexContext); ok {
+		return v.VisitDropIndex(tmp)
+	}
+	if tmp, ok := ctx.DropTable().(*DropTableContext); ok {
+		return v.VisitDropTable(tmp)
+	}
+	if tmp, ok := ctx.RenameTable().(*RenameTableContext); ok {
+		return v.VisitRenameTable(tmp)
+	}
+	if tmp, ok := ctx.TruncateTable().(*TruncateTableContext); ok {
+		return v.VisitTruncateTable(tmp)
+	}
+	return nil
+}
+
+func (v *Visitor) VisitAdministrationStatement(ctx *AdministrationStatementContext) interface{} {
+	if tmp := ctx.CreateUser(); tmp != nil {
+		return v.VisitCreateUser(tmp)
+	}
+	if tmp, ok := ctx.DropUser().(*DropUserContext); ok {
+		return v.VisitDropUser(tmp)
+	}
+	if tmp, ok := ctx.GrantStatement().(*GrantStatementContext); ok {
+		return v.VisitGrantStatement(tmp)
+	}
+	if tmp := ctx.RevokeStatement(); tmp != nil {
+		return v.VisitRevokeStatement(tmp)
+	}
+	if tmp := ctx.SetStatement(); tmp != nil {
+		return v.VisitSetStatement(tmp)
+	}
+	slog.Warn("unsupport AdministrationStatement")
+	return nil
+}
+
+func (v *Visitor) VisitUtilityStatement(ctx *UtilityStatementContext) interface{} {
+	if tmp, ok := ctx.UseStatement().(*UseStatementContext); ok {
+		return v.VisitUseStatement(tmp)
+	}
+	slog.Warn("unsupport UtilityStatement")
+	return nil
+}
+
+// sqlstatement end
+
+// VisitFullId return *TableName, the schema is the current database if not qualified
+func (v *Visitor) VisitFullId(ctx *FullIdContext) interface{} {
+	var res TableName
+	var names []string
+	for _, uid := range ctx.AllUid() {
+		if uu, ok := uid.(*UidContext); ok {
+			if name, ok := v.VisitUid(uu).(string); ok {
+				names = append(names, name)
+			}
+		}
+	}
+	if dot := ctx.DOT_ID(); dot != nil {
+		names = append(names, strings.TrimPrefix(dot.GetText(), "."))
+	}
+	switch len(names) {
+	case 0:
+		return nil
+	case 1:
+		res.Schema = v.database
+		res.Name = names[0]
+	default:
+		res.Schema = names[0]
+		res.Name = names[1]
+	}
+	return &res
+}
+
+func (v *Visitor) VisitTableName(ctx *TableNameContext) interface{} {
+	if fullIdCtx, ok := ctx.FullId().(*FullIdContext); ok {
+		return v.VisitFullId(fullIdCtx)
+	}
+	return nil
+}
+
+func (v *Visitor) VisitTables(ctx *TablesContext) interface{} {
+	var res []*TableName
+	for _, tbl := range ctx.AllTableName() {
+		if tblCtx, ok := tbl.(*TableNameContext); ok {
+			if name, ok := v.VisitTableName(tblCtx).(*TableName); ok {
+				res = append(res, name)
+			}
+		}
+	}
+	return res
+}
+
+// sourceText return the original text between start and stop token, whitespace and comments kept
+func sourceText(start, stop antlr.Token) string {
+	if start == nil || stop == nil || stop.GetStop() < start.GetStart() {
+		return ""
+	}
+	return start.GetInputStream().GetText(start.GetStart(), stop.GetStop())
+}
+
+// sourceSpan return the location between start and stop token
+func sourceSpan(start, stop antlr.Token) Span {
+	if start == nil || stop == nil {
+		return Span{}
+	}
+	return Span{
+		Start:  start.GetStart(),
+		Stop:   stop.GetStop(),
+		Line:   start.GetLine(),
+		Column: start.GetColumn(),
+	}
+}
+
+// referencedTables collect every table name in the tree except common table expressions,
+// duplicates removed
+func (v *Visitor) referencedTables(tree antlr.Tree) []*TableName {
+	var res []*TableName
+	ctes := map[string]bool{}
+	seen := map[string]bool{}
+	var names []*TableNameContext
+
+	var walk func(node antlr.Tree)
+	walk = func(node antlr.Tree) {
+		switch tx := node.(type) {
+		case *CteNameContext:
+			if uu, ok := tx.Uid().(*UidContext); ok {
+				if name, ok := v.VisitUid(uu).(string); ok {
+					ctes[strings.ToLower(name)] = true
+				}
+			}
+		case *TableNameContext:
+			names = append(names, tx)
+		}
+		for _, child := range node.GetChildren() {
+			walk(child)
+		}
+	}
+	walk(tree)
+
+	for _, nameCtx := range names {
+		name, ok := v.VisitTableName(nameCtx).(*TableName)
+		if !ok {
+			continue
+		}
+		if ctes[strings.ToLower(name.Name)] && !strings.Contains(nameCtx.GetText(), ".") {
+			continue
+		}
+		if seen[name.String()] {
+			continue
+		}
+		seen[name.String()] = true
+		res = append(res, name)
+	}
+	return res
+}
+
+// writtenTables collect the target tables of INSERT, REPLACE, UPDATE and DELETE in the tree,
+// duplicates removed
+func (v *Visitor) writtenTables(tree antlr.Tree) []*TableName {
+	var res []*TableName
+	seen := map[string]bool{}
+	add := func(nameCtx ITableNameContext) {
+		if tblCtx, ok := nameCtx.(*TableNameContext); ok {
+			if name, ok := v.VisitTableName(tblCtx).(*TableName); ok && !seen[name.String()] {
+				seen[name.String()] = true
+				res = append(res, name)
+			}
+		}
+	}
+
+	var walk func(node antlr.Tree)
+	walk = func(node antlr.Tree) {
+		switch tx := node.(type) {
+		case *InsertStatementContext:
+			add(tx.TableName())
+		case *ReplaceStatementContext:
+			add(tx.TableName())
+		case *SingleUpdateStatementContext:
+			add(tx.TableName())
+		case *SingleDeleteStatementContext:
+			add(tx.TableName())
+		case *MultipleDeleteStatementContext:
+			for _, nameCtx := range tx.AllTableName() {
+				add(nameCtx)
+			}
+		case *MultipleUpdateStatementContext:
+			// every joined table may be updated
+			for _, item := range v.referencedTables(tx.TableSources()) {
+				if !seen[item.String()] {
+					seen[item.String()] = true
+					res = append(res, item)
+				}
+			}
+		}
+		for _, child := range node.GetChildren() {
+			walk(child)
+		}
+	}
+	walk(tree)
+	return res
+}
+
+// visitRoutineBody return the body of trigger, stored routine and event
+func (v *Visitor) visitRoutineBody(ctx IRoutineBodyContext) *RoutineBody {
+	var res RoutineBody
+	if ctx == nil {
+		return nil
+	}
+	res.Span = sourceSpan(ctx.GetStart(), ctx.GetStop())
+	res.Source = sourceText(ctx.GetStart(), ctx.GetStop())
+	if blockCtx := ctx.BlockStatement(); blockCtx != nil {
+		for _, stmt := range blockCtx.AllProcedureSqlStatement() {
+			var inner antlr.ParserRuleContext
+			if tmp := stmt.CompoundStatement(); tmp != nil {
+				inner = tmp
+			} else if tmp := stmt.SqlStatement(); tmp != nil {
+				inner = tmp
+			}
+			if inner != nil {
+				res.Statements = append(res.Statements, sourceText(inner.GetStart(), inner.GetStop()))
+			}
+		}
+	} else if stmtCtx := ctx.SqlStatement(); stmtCtx != nil {
+		res.Statements = append(res.Statements, sourceText(stmtCtx.GetStart(), stmtCtx.GetStop()))
+	}
+	res.Tables = v.referencedTables(ctx)
+	res.Writes = v.writtenTables(ctx)
+	return &res
+}
+
+// visitOwnerStatement return the definer like root@localhost or CURRENT_USER
+func (v *Visitor) visitOwnerStatement(ctx IOwnerStatementContext) string {
+	if ctx == nil {
+		return ""
+	}
+	if ctx.CurrentUserExpression() != nil {
+		return "CURRENT_USER"
+	}
+	if ctx.UserName() != nil {
+		return WithReplacer(ctx.UserName().GetText(), "'", "", "`", "", "\"", "")
+	}
+	return ""
+}
+
+// --- dropTable, renameTable, truncateTable start
+
+func (v *Visitor) VisitDropTable(ctx *DropTableContext) interface{} {
+	var res DropTable
+	res.Temporary = ctx.TEMPORARY() != nil
+	res.IfExists = ctx.IfExists() != nil
+	res.Restrict = ctx.RESTRICT() != nil
+	res.Cascade = ctx.CASCADE() != nil
+	if tablesCtx, ok := ctx.Tables().(*TablesContext); ok {
+		if val, ok := v.VisitTables(tablesCtx).([]*TableName); ok {
+			res.Tables = val
+		}
+	}
+	slog.Debug("VisitDropTable", "tables", len(res.Tables))
+	return &res
+}
+
+func (v *Visitor) VisitRenameTable(ctx *RenameTableContext) interface{} {
+	var res RenameTable
+	for _, clause := range ctx.AllRenameTableClause() {
+		if clauseCtx, ok := clause.(*RenameTableClauseContext); ok {
+			if val, ok := v.VisitRenameTableClause(clauseCtx).(*RenameTableClause); ok {
+				res.Clauses = append(res.Clauses, val)
+			}
+		}
+	}
+	return &res
+}
+
+func (v *Visitor) VisitRenameTableClause(ctx *RenameTableClauseContext) interface{} {
+	var res RenameTableClause
+	names := ctx.AllTableName()
+	if len(names) != 2 {
+		return nil
+	}
+	if tblCtx, ok := names[0].(*TableNameContext); ok {
+		res.From, _ = v.VisitTableName(tblCtx).(*TableName)
+	}
+	if tblCtx, ok := names[1].(*TableNameContext); ok {
+		res.To, _ = v.VisitTableName(tblCtx).(*TableName)
+	}
+	return &res
+}
+
+func (v *Visitor) VisitTruncateTable(ctx *TruncateTableContext) interface{} {
+	var res TruncateTable
+	if tblCtx, ok := ctx.TableName().(*TableNameContext); ok {
+		res.Table, _ = v.VisitTableName(tblCtx).(*TableName)
+	}
+	return &res
+}
+
+// --- dropTable, renameTable, truncateTable end
+
+// --- view start
+
+func (v *Visitor) VisitCreateView(ctx *CreateViewContext) interface{} {
+	var res CreateView
+	var view View
+	res.OrReplace = ctx.OrReplace() != nil
+
+	if idCtx, ok := ctx.FullId().(*FullIdContext); ok {
+		view.Name, _ = v.VisitFullId(idCtx).(*TableName)
+	}
+	if tk := ctx.GetAlgType(); tk != nil {
+		view.Algorithm = strings.ToUpper(tk.GetText())
+	}
+	view.Definer = v.visitOwnerStatement(ctx.OwnerStatement())
+	if tk := ctx.GetSecContext(); tk != nil {
+		view.SQLSecurity = strings.ToUpper(tk.GetText())
+	}
+	if listCtx, ok := ctx.UidList().(*UidListContext); ok {
+		view.Columns, _ = v.VisitUidList(listCtx).([]string)
+	}
+	if selectCtx := ctx.SelectStatement(); selectCtx != nil {
+		start := selectCtx.GetStart()
+		if withCtx := ctx.WithClause(); withCtx != nil {
+			start = withCtx.GetStart()
+		}
+		view.Select = sourceText(start, selectCtx.GetStop())
+		view.Tables = v.referencedTables(ctx)
+	}
+	if ctx.CHECK() != nil {
+		view.WithCheckOption = true
+		if tk := ctx.GetCheckOption(); tk != nil {
+			view.CheckOption = strings.ToUpper(tk.GetText())
+		}
+	}
+	res.View = &view
+	return &res
+}
+
+func (v *Visitor) VisitAlterView(ctx *AlterViewContext) interface{} {
+	var res AlterView
+	var view View
+
+	if idCtx, ok := ctx.FullId().(*FullIdContext); ok {
+		view.Name, _ = v.VisitFullId(idCtx).(*TableName)
+	}
+	if tk := ctx.GetAlgType(); tk != nil {
+		view.Algorithm = strings.ToUpper(tk.GetText())
+	}
+	view.Definer = v.visitOwnerStatement(ctx.OwnerStatement())
+	if tk := ctx.GetSecContext(); tk != nil {
+		view.SQLSecurity = strings.ToUpper(tk.GetText())
+	}
+	if listCtx, ok := ctx.UidList().(*UidListContext); ok {
+		view.Columns, _ = v.VisitUidList(listCtx).([]string)
+	}
+	if selectCtx := ctx.SelectStatement(); selectCtx != nil {
+		view.Select = sourceText(selectCtx.GetStart(), selectCtx.GetStop())
+		view.Tables = v.referencedTables(selectCtx)
+	}
+	if ctx.CHECK() != nil {
+		view.WithCheckOption = true
+		if tk := ctx.GetCheckOpt(); tk != nil {
+			view.CheckOption = strings.ToUpper(tk.GetText())
+		}
+	}
+	res.View = &view
+	return &res
+}
+
+func (v *Visitor) VisitDropView(ctx *DropViewContext) interface{} {
+	var res DropView
+	res.IfExists = ctx.IfExists() != nil
+	res.Restrict = ctx.RESTRICT() != nil
+	res.Cascade = ctx.CASCADE() != nil
+	for _, id := range ctx.AllFullId() {
+		if idCtx, ok := id.(*FullIdContext); ok {
+			if name, ok := v.VisitFullId(idCtx).(*TableName); ok {
+				res.Views = append(res.Views, name)
+			}
+		}
+	}
+	return &res
+}
+
+// --- view end
+
+// --- trigger start
+
+func (v *Visitor) VisitCreateTrigger(ctx *CreateTriggerContext) interface{} {
+	var res CreateTrigger
+	var trigger Trigger
+
+	if idCtx, ok := ctx.GetThisTrigger().(*FullIdContext); ok {
+		trigger.Name, _ = v.VisitFullId(idCtx).(*TableName)
+	}
+	trigger.Definer = v.visitOwnerStatement(ctx.OwnerStatement())
+	if tk := ctx.GetTriggerTime(); tk != nil {
+		trigger.Time = strings.ToUpper(tk.GetText())
+	}
+	if tk := ctx.GetTriggerEvent(); tk != nil {
+		trigger.Event = strings.ToUpper(tk.GetText())
+	}
+	if tblCtx, ok := ctx.TableName().(*TableNameContext); ok {
+		trigger.Table, _ = v.VisitTableName(tblCtx).(*TableName)
+	}
+	if tk := ctx.GetTriggerPlace(); tk != nil {
+		trigger.Order = strings.ToUpper(tk.GetText())
+		if idCtx, ok := ctx.GetOtherTrigger().(*FullIdContext); ok {
+			trigger.OtherTrigger, _ = v.VisitFullId(idCtx).(*TableName)
+		}
+	}
+	trigger.Body = v.visitRoutineBody(ctx.RoutineBody())
+
+	res.Trigger = &trigger
+	return &res
+}
+
+func (v *Visitor) VisitDropTrigger(ctx *DropTriggerContext) interface{} {
+	var res DropTrigger
+	res.IfExists = ctx.IfExists() != nil
+	if idCtx, ok := ctx.FullId().(*FullIdContext); ok {
+		res.Name, _ = v.VisitFullId(idCtx).(*TableName)
+	}
+	return &res
+}
+
+// --- trigger end
+
+// --- routine start
+
+func (v *Visitor) VisitCreateProcedure(ctx *CreateProcedureContext) interface{} {
+	var res CreateProcedure
+	var proc Procedure
+
+	if idCtx, ok := ctx.FullId().(*FullIdContext); ok {
+		proc.Name, _ = v.VisitFullId(idCtx).(*TableName)
+	}
+	proc.Definer = v.visitOwnerStatement(ctx.OwnerStatement())
+	for _, param := range ctx.AllProcedureParameter() {
+		if paramCtx, ok := param.(*ProcedureParameterContext); ok {
+			if val, ok := v.VisitProcedureParameter(paramCtx).(*RoutineParameter); ok {
+				proc.Parameters = append(proc.Parameters, val)
+			}
+		}
+	}
+	proc.Characteristics = v.visitRoutineOptions(ctx.AllRoutineOption())
+	proc.Body = v.visitRoutineBody(ctx.RoutineBody())
+
+	res.Procedure = &proc
+	return &res
+}
+
+func (v *Visitor) VisitCreateFunction(ctx *CreateFunctionContext) interface{} {
+	var res CreateFunction
+	var fn Function
+	res.IfNotExists = ctx.IfNotExists() != nil
+
+	if idCtx, ok := ctx.FullId().(*FullIdContext); ok {
+		fn.Name, _ = v.VisitFullId(idCtx).(*TableName)
+	}
+	fn.Definer = v.visitOwnerStatement(ctx.OwnerStatement())
+	fn.Aggregate = ctx.AGGREGATE() != nil
+	for _, param := range ctx.AllFunctionParameter() {
+		if paramCtx, ok := param.(*FunctionParameterContext); ok {
+			if val, ok := v.VisitFunctionParameter(paramCtx).(*RoutineParameter); ok {
+				fn.Parameters = append(fn.Parameters, val)
+			}
+		}
+	}
+	if ctx.DataType() != nil {
+		fn.Returns, _ = v.VisitDataType(ctx.DataType()).(*DataType)
+	}
+	fn.Characteristics = v.visitRoutineOptions(ctx.AllRoutineOption())
+	if bodyCtx := ctx.RoutineBody(); bodyCtx != nil {
+		fn.Body = v.visitRoutineBody(bodyCtx)
+	} else if retCtx := ctx.ReturnStatement(); retCtx != nil {
+		// RETURN expression without BEGIN ... END
+		text := sourceText(retCtx.GetStart(), retCtx.GetStop())
+		fn.Body = &RoutineBody{
+			Span:       sourceSpan(retCtx.GetStart(), retCtx.GetStop()),
+			Source:     text,
+			Statements: []string{text},
+			Tables:     v.referencedTables(retCtx),
+		}
+	}
+
+	res.Function = &fn
+	return &res
+}
+
+func (v *Visitor) VisitProcedureParameter(ctx *ProcedureParameterContext) interface{} {
+	var res RoutineParameter
+	if tk := ctx.GetDirection(); tk != nil {
+		res.Direction = strings.ToUpper(tk.GetText())
+	}
+	if uu, ok := ctx.Uid().(*UidContext); ok {
+		res.Name, _ = v.VisitUid(uu).(string)
+	}
+	if ctx.DataType() != nil {
+		res.DataType, _ = v.VisitDataType(ctx.DataType()).(*DataType)
+	}
+	return &res
+}
+
+func (v *Visitor) VisitFunctionParameter(ctx *FunctionParameterContext) interface{} {
+	var res RoutineParameter
+	if uu, ok := ctx.Uid().(*UidContext); ok {
+		res.Name, _ = v.VisitUid(uu).(string)
+	}
+	if ctx.DataType() != nil {
+		res.DataType, _ = v.VisitDataType(ctx.DataType()).(*DataType)
+	}
+	return &res
+}
+
+// visitRoutineOptions return the characteristics, nil if no routineOption
+func (v *Visitor) visitRoutineOptions(options []IRoutineOptionContext) *RoutineCharacteristics {
+	var res RoutineCharacteristics
+	if len(options) == 0 {
+		return nil
+	}
+	for _, op := range options {
+		switch tx := op.(type) {
+		case *RoutineCommentContext:
+			res.Comment = WithUnquote(tx.STRING_LITERAL().GetText())
+		case *RoutineLanguageContext:
+			res.Language = "SQL"
+		case *RoutineBehaviorContext:
+			res.Deterministic = tx.NOT() == nil
+		case *RoutineDataContext:
+			var words []string
+			for _, child := range tx.GetChildren() {
+				if node, ok := child.(antlr.TerminalNode); ok {
+					words = append(words, strings.ToUpper(node.GetText()))
+				}
+			}
+			res.DataAccess = strings.Join(words, " ")
+		case *RoutineSecurityContext:
+			if tk := tx.GetContext(); tk != nil {
+				res.SQLSecurity = strings.ToUpper(tk.GetText())
+			}
+		}
+	}
+	return &res
+}
+
+func (v *Visitor) VisitDropProcedure(ctx *DropProcedureContext) interface{} {
+	var res DropProcedure
+	res.IfExists = ctx.IfExists() != nil
+	if idCtx, ok := ctx.FullId().(*FullIdContext); ok {
+		res.Name, _ = v.VisitFullId(idCtx).(*TableName)
+	}
+	return &res
+}
+
+func (v *Visitor) VisitDropFunction(ctx *DropFunctionContext) interface{} {
+	var res DropFunction
+	res.IfExists = ctx.IfExists() != nil
+	if idCtx, ok := ctx.FullId().(*FullIdContext); ok {
+		res.Name, _ = v.VisitFullId(idCtx).(*TableName)
+	}
+	return &res
+}
+
+// --- routine end
+
+// --- event start
+
+// eventTimeLayouts are the accepted layouts of datetime literal
+var eventTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+func (v *Visitor) VisitCreateEvent(ctx *CreateEventContext) interface{} {
+	var res CreateEvent
+	var event Event
+	res.IfNotExists = ctx.IfNotExists() != nil
+
+	if idCtx, ok := ctx.FullId().(*FullIdContext); ok {
+		event.Name, _ = v.VisitFullId(idCtx).(*TableName)
+	}
+	event.Definer = v.visitOwnerStatement(ctx.OwnerStatement())
+	if ctx.ScheduleExpression() != nil {
+		event.Schedule = v.visitScheduleExpression(ctx.ScheduleExpression())
+	}
+	if ctx.PRESERVE() != nil {
+		event.OnCompletion = "PRESERVE"
+		if ctx.NOT() != nil {
+			event.OnCompletion = "NOT PRESERVE"
+		}
+	}
+	if enableCtx, ok := ctx.EnableType().(*EnableTypeContext); ok {
+		event.Status, _ = v.VisitEnableType(enableCtx).(string)
+	}
+	if str := ctx.STRING_LITERAL(); str != nil {
+		event.Comment = WithUnquote(str.GetText())
+	}
+	event.Body = v.visitRoutineBody(ctx.RoutineBody())
+
+	res.Event = &event
+	return &res
+}
+
+func (v *Visitor) VisitAlterEvent(ctx *AlterEventContext) interface{} {
+	var res AlterEvent
+	var event Event
+
+	ids := ctx.AllFullId()
+	if len(ids) > 0 {
+		if idCtx, ok := ids[0].(*FullIdContext); ok {
+			event.Name, _ = v.VisitFullId(idCtx).(*TableName)
+		}
+	}
+	if len(ids) > 1 {
+		if idCtx, ok := ids[1].(*FullIdContext); ok {
+			res.RenameTo, _ = v.VisitFullId(idCtx).(*TableName)
+		}
+	}
+	event.Definer = v.visitOwnerStatement(ctx.OwnerStatement())
+	if ctx.ScheduleExpression() != nil {
+		event.Schedule = v.visitScheduleExpression(ctx.ScheduleExpression())
+	}
+	if ctx.PRESERVE() != nil {
+		event.OnCompletion = "PRESERVE"
+		if ctx.NOT() != nil {
+			event.OnCompletion = "NOT PRESERVE"
+		}
+	}
+	if enableCtx, ok := ctx.EnableType().(*EnableTypeContext); ok {
+		event.Status, _ = v.VisitEnableType(enableCtx).(string)
+	}
+	if str := ctx.STRING_LITERAL(); str != nil {
+		event.Comment = WithUnquote(str.GetText())
+	}
+	if ctx.RoutineBody() != nil {
+		event.Body = v.visitRoutineBody(ctx.RoutineBody())
+	}
+
+	res.Event = &event
+	return &res
+}
+
+func (v *Visitor) VisitDropEvent(ctx *DropEventContext) interface{} {
+	var res DropEvent
+	res.IfExists = ctx.IfExists() != nil
+	if idCtx, ok := ctx.FullId().(*FullIdContext); ok {
+		res.Name, _ = v.VisitFullId(idCtx).(*TableName)
+	}
+	return &res
+}
+
+func (v *Visitor) VisitEnableType(ctx *EnableTypeContext) interface{} {
+	if ctx.ENABLE() != nil {
+		return "ENABLE"
+	}
+	if ctx.SLAVE() != nil {
+		return "DISABLE ON SLAVE"
+	}
+	return "DISABLE"
+}
+
+func (v *Visitor) visitScheduleExpression(ctx IScheduleExpressionContext) *Schedule {
+	var res Schedule
+	switch tx := ctx.(type) {
+	case *PreciseScheduleContext:
+		res.At = v.visitEventTimestamp(tx.TimestampValue(), tx.AllIntervalExpr())
+	case *IntervalScheduleContext:
+		var every Interval
+		if tx.DecimalLiteral() != nil {
+			every.Value = tx.DecimalLiteral().GetText()
+		} else if tx.Expression() != nil {
+			every.Value = WithTrimQuote(sourceText(tx.Expression().GetStart(), tx.Expression().GetStop()))
+		}
+		if tx.IntervalType() != nil {
+			every.Unit = strings.ToUpper(tx.IntervalType().GetText())
+		}
+		res.Every = &every
+		if tx.GetStartTimestamp() != nil {
+			res.Starts = v.visitEventTimestamp(tx.GetStartTimestamp(), tx.GetStartIntervals())
+		}
+		if tx.GetEndTimestamp() != nil {
+			res.Ends = v.visitEventTimestamp(tx.GetEndTimestamp(), tx.GetEndIntervals())
+		}
+	default:
+		return nil
+	}
+	return &res
+}
+
+func (v *Visitor) visitEventTimestamp(ctx ITimestampValueContext, intervals []IIntervalExprContext) *EventTimestamp {
+	var res EventTimestamp
+	if ctx == nil {
+		return nil
+	}
+	switch {
+	case ctx.CURRENT_TIMESTAMP() != nil:
+		res.Value = "CURRENT_TIMESTAMP"
+	case ctx.StringLiteral() != nil:
+		res.Value = WithTrimQuote(ctx.StringLiteral().GetText())
+		for _, layout := range eventTimeLayouts {
+			if t, err := time.Parse(layout, res.Value); err == nil {
+				res.Time = t
+				break
+			}
+		}
+	default:
+		res.Value = sourceText(ctx.GetStart(), ctx.GetStop())
+	}
+	for _, interval := range intervals {
+		if intervalCtx, ok := interval.(*IntervalExprContext); ok {
+			if val, ok := v.VisitIntervalExpr(intervalCtx).(*Interval); ok {
+				res.Intervals = append(res.Intervals, val)
+			}
+		}
+	}
+	return &res
+}
+
+func (v *Visitor) VisitIntervalExpr(ctx *IntervalExprContext) interface{} {
+	var res Interval
+	if ctx.DecimalLiteral() != nil {
+		res.Value = ctx.DecimalLiteral().GetText()
+	} else if ctx.Expression() != nil {
+		res.Value = WithTrimQuote(sourceText(ctx.Expression().GetStart(), ctx.Expression().GetStop()))
+	}
+	if ctx.IntervalType() != nil {
+		res.Unit = strings.ToUpper(ctx.IntervalType().GetText())
+	}
+	return &res
+}
+
+// --- event end
+
+// --- account start
+
+// visitPrincipal return the account of userName, uid, roleName or userAuthOption
+func (v *Visitor) visitPrincipal(node antlr.Tree) *Principal {
+	var res Principal
+	switch tx := node.(type) {
+	case *UserNameContext:
+		if tx.CurrentUserExpression() != nil {
+			res.User = "CURRENT_USER"
+			return &res
+		}
+		if tx.SimpleUserName() != nil {
+			res.User = WithTrimQuote(tx.SimpleUserName().GetText())
+		}
+		if tx.HostName() != nil {
+			res.Host = WithTrimQuote(strings.TrimPrefix(tx.HostName().GetText(), "@"))
+		}
+		return &res
+	case *UidContext:
+		res.User, _ = v.VisitUid(tx).(string)
+		return &res
+	case *RoleNameContext:
+		if tx.UserName() != nil {
+			return v.visitPrincipal(tx.UserName())
+		}
+		if tx.Uid() != nil {
+			return v.visitPrincipal(tx.Uid())
+		}
+	case interface{ UserName() IUserNameContext }:
+		// the alternatives of userAuthOption, the password is ignored
+		if tx.UserName() != nil {
+			return v.visitPrincipal(tx.UserName())
+		}
+	}
+	return nil
+}
+
+// splitPrincipals collect the accounts of the direct children of ctx,
+// divided by the keyword TO or FROM
+func (v *Visitor) splitPrincipals(ctx antlr.ParserRuleContext, keyword int) (before, after []*Principal) {
+	divided := false
+	for _, child := range ctx.GetChildren() {
+		if node, ok := child.(antlr.TerminalNode); ok {
+			if node.GetSymbol().GetTokenType() == keyword {
+				divided = true
+			}
+			continue
+		}
+		principal := v.visitPrincipal(child)
+		if principal == nil {
+			continue
+		}
+		if divided {
+			after = append(after, principal)
+		} else {
+			before = append(before, principal)
+		}
+	}
+	return before, after
+}
+
+func (v *Visitor) VisitCreateUser(ctx ICreateUserContext) interface{} {
+	var res CreateUser
+	var options []IUserAuthOptionContext
+
+	switch tx := ctx.(type) {
+	case *CreateUserMysqlV56Context:
+		options = tx.AllUserAuthOption()
+	case *CreateUserMysqlV80Context:
+		res.IfNotExists = tx.IfNotExists() != nil
+		options = tx.AllUserAuthOption()
+		if roleCtx, ok := tx.RoleOption().(*RoleOptionContext); ok {
+			_, res.DefaultRoles, _ = v.visitRoleOption(roleCtx)
+		}
+	default:
+		return nil
+	}
+	for _, op := range options {
+		if principal := v.visitPrincipal(op); principal != nil {
+			res.Users = append(res.Users, principal)
+		}
+	}
+	return &res
+}
+
+func (v *Visitor) VisitDropUser(ctx *DropUserContext) interface{} {
+	var res DropUser
+	res.IfExists = ctx.IfExists() != nil
+	for _, name := range ctx.AllUserName() {
+		if principal := v.visitPrincipal(name); principal != nil {
+			res.Users = append(res.Users, principal)
+		}
+	}
+	return &res
+}
+
+func (v *Visitor) VisitCreateRole(ctx *CreateRoleContext) interface{} {
+	var res CreateRole
+	res.IfNotExists = ctx.IfNotExists() != nil
+	for _, name := range ctx.AllRoleName() {
+		if principal := v.visitPrincipal(name); principal != nil {
+			res.Roles = append(res.Roles, principal)
+		}
+	}
+	return &res
+}
+
+func (v *Visitor) VisitDropRole(ctx *DropRoleContext) interface{} {
+	var res DropRole
+	res.IfExists = ctx.IfExists() != nil
+	for _, name := range ctx.AllRoleName() {
+		if principal := v.visitPrincipal(name); principal != nil {
+			res.Roles = append(res.Roles, principal)
+		}
+	}
+	return &res
+}
+
+// VisitGrantStatement return *Grant for privileges, *GrantRole for roles
+func (v *Visitor) VisitGrantStatement(ctx *GrantStatementContext) interface{} {
+	if ctx.PrivilegeLevel() == nil {
+		var res GrantRole
+		res.Roles, res.To = v.splitPrincipals(ctx, MySqlParserTO)
+		res.WithAdminOption = ctx.ADMIN() != nil
+		return &res
+	}
+
+	var res Grant
+	res.Privileges = v.visitPrivelegeClauses(ctx.AllPrivelegeClause())
+	res.Object = v.visitPrivilegeLevel(ctx.PrivilegeLevel(), ctx.GetPrivilegeObject())
+	for _, op := range ctx.AllUserAuthOption() {
+		if principal := v.visitPrincipal(op); principal != nil {
+			res.To = append(res.To, principal)
+		}
+	}
+	// the first GRANT is the statement keyword
+	res.WithGrantOption = len(ctx.AllGRANT()) > 1
+	return &res
+}
+
+// VisitRevokeStatement return *Revoke for privileges, *RevokeRole for roles
+func (v *Visitor) VisitRevokeStatement(ctx IRevokeStatementContext) interface{} {
+	switch tx := ctx.(type) {
+	case *DetailRevokeContext:
+		var res Revoke
+		res.Privileges = v.visitPrivelegeClauses(tx.AllPrivelegeClause())
+		res.Object = v.visitPrivilegeLevel(tx.PrivilegeLevel(), tx.GetPrivilegeObject())
+		for _, name := range tx.AllUserName() {
+			if principal := v.visitPrincipal(name); principal != nil {
+				res.From = append(res.From, principal)
+			}
+		}
+		return &res
+	case *ShortRevokeContext:
+		var res Revoke
+		res.Privileges = []*Privilege{{Type: "ALL PRIVILEGES"}, {Type: "GRANT OPTION"}}
+		for _, name := range tx.AllUserName() {
+			if principal := v.visitPrincipal(name); principal != nil {
+				res.From = append(res.From, principal)
+			}
+		}
+		return &res
+	case *RoleRevokeContext:
+		var res RevokeRole
+		res.Roles, res.From = v.splitPrincipals(tx, MySqlParserFROM)
+		return &res
+	}
+	return nil
+}
+
+func (v *Visitor) VisitSetRole(ctx *SetRoleContext) interface{} {
+	var res SetRole
+	if ctx.DEFAULT() != nil {
+		// SET DEFAULT ROLE (NONE | ALL | roles) TO users
+		res.Default = true
+		switch {
+		case ctx.NONE() != nil:
+			res.Option = "NONE"
+		case ctx.ALL() != nil:
+			res.Option = "ALL"
+		}
+		res.Roles, res.To = v.splitPrincipals(ctx, MySqlParserTO)
+		return &res
+	}
+	if roleCtx, ok := ctx.RoleOption().(*RoleOptionContext); ok {
+		res.Option, res.Roles, res.Except = v.visitRoleOption(roleCtx)
+	}
+	return &res
+}
+
+// visitRoleOption return the option DEFAULT, NONE or ALL, the listed roles and the ALL EXCEPT roles
+func (v *Visitor) visitRoleOption(ctx *RoleOptionContext) (option string, roles, except []*Principal) {
+	var names []*Principal
+	for _, name := range ctx.AllUserName() {
+		if principal := v.visitPrincipal(name); principal != nil {
+			names = append(names, principal)
+		}
+	}
+	switch {
+	case ctx.DEFAULT() != nil:
+		return "DEFAULT", nil, nil
+	case ctx.NONE() != nil:
+		return "NONE", nil, nil
+	case ctx.ALL() != nil:
+		return "ALL", nil, names
+	}
+	return "", names, nil
+}
+
+func (v *Visitor) visitPrivelegeClauses(clauses []IPrivelegeClauseContext) []*Privilege {
+	var res []*Privilege
+	for _, clause := range clauses {
+		var privilege Privilege
+		if clause.Privilege() == nil {
+			continue
+		}
+		var words []string
+		for _, child := range clause.Privilege().GetChildren() {
+			if node, ok := child.(antlr.TerminalNode); ok {
+				words = append(words, strings.ToUpper(node.GetText()))
+			}
+		}
+		privilege.Type = strings.Join(words, " ")
+		if listCtx, ok := clause.UidList().(*UidListContext); ok {
+			privilege.Columns, _ = v.VisitUidList(listCtx).([]string)
+		}
+		res = append(res, &privilege)
+	}
+	return res
+}
+
+// visitPrivilegeLevel return the object, the unqualified name use the current database
+func (v *Visitor) visitPrivilegeLevel(ctx IPrivilegeLevelContext, objectType antlr.Token) *PrivilegeObject {
+	var res PrivilegeObject
+	uid := func(ctx IUidContext) string {
+		if uu, ok := ctx.(*UidContext); ok {
+			name, _ := v.VisitUid(uu).(string)
+			return name
+		}
+		return ""
+	}
+
+	switch tx := ctx.(type) {
+	case *GlobalPrivLevelContext:
+		res.Scope = ScopeGlobal
+	case *CurrentSchemaPriviLevelContext:
+		res.Scope = ScopeDatabase
+		res.Schema = v.database
+	case *DefiniteSchemaPrivLevelContext:
+		res.Scope = ScopeDatabase
+		res.Schema = uid(tx.Uid())
+	case *DefiniteFullTablePrivLevelContext:
+		res.Scope = ScopeTable
+		res.Schema = uid(tx.Uid(0))
+		res.Name = uid(tx.Uid(1))
+	case *DefiniteFullTablePrivLevel2Context:
+		res.Scope = ScopeTable
+		res.Schema = uid(tx.Uid())
+		if dotCtx := tx.DottedId(); dotCtx != nil {
+			if dotCtx.Uid() != nil {
+				res.Name = uid(dotCtx.Uid())
+			} else {
+				res.Name = strings.TrimPrefix(dotCtx.GetText(), ".")
+			}
+		}
+	case *DefiniteTablePrivLevelContext:
+		res.Scope = ScopeTable
+		res.Schema = v.database
+		res.Name = uid(tx.Uid())
+	default:
+		return nil
+	}
+	if objectType != nil {
+		res.ObjectType = strings.ToUpper(objectType.GetText())
+		if res.ObjectType != "TABLE" {
+			res.Scope = ScopeRoutine
+		}
+	}
+	return &res
 }
 
-var _ MySqlParserVisitor = (*Visitor)(nil)
+// --- account end
 
-func (v *Visitor) VisitUid(ctx *UidContext) interface{} {
-	str := ctx.GetText()
-	str = WithTrimQuote(str)
-	str = WithReplacer(str, "\r", "", "\n", "")
-	return str
+// --- set start
+
+func (v *Visitor) VisitSetStatement(ctx ISetStatementContext) interface{} {
+	switch tx := ctx.(type) {
+	case *SetVariableContext:
+		return v.VisitSetVariable(tx)
+	case *SetNamesContext:
+		return v.VisitSetNames(tx)
+	case *SetCharsetContext:
+		return v.VisitSetCharset(tx)
+	case *SetAutocommitContext:
+		var res SetVariable
+		if stmt := tx.SetAutocommitStatement(); stmt != nil && stmt.GetAutocommitValue() != nil {
+			res.Assignments = append(res.Assignments, &VariableAssignment{
+				Scope: VariableSession,
+				Name:  "autocommit",
+				Value: stmt.GetAutocommitValue().GetText(),
+			})
+		}
+		return &res
+	}
+	slog.Warn("unsupport SetStatement")
+	return nil
 }
 
-func (v *Visitor) VisitIndexColumnName(ctx *IndexColumnNameContext) interface{} {
-	var col string
-	if ctx.Uid() != nil {
-		uidCtx := ctx.Uid().(*UidContext)
-		tmp := v.VisitUid(uidCtx)
-		if val, ok := tmp.(string); ok {
-			col = val
+func (v *Visitor) VisitSetVariable(ctx *SetVariableContext) interface{} {
+	var res SetVariable
+	var current *VariableAssignment
+	for _, child := range ctx.GetChildren() {
+		switch tx := child.(type) {
+		case *VariableClauseContext:
+			current, _ = v.VisitVariableClause(tx).(*VariableAssignment)
+		case IExpressionContext:
+			if current != nil {
+				current.Value = sourceText(tx.GetStart(), tx.GetStop())
+				res.Assignments = append(res.Assignments, current)
+				current = nil
+			}
+		case antlr.TerminalNode:
+			if tx.GetSymbol().GetTokenType() == MySqlParserON && current != nil {
+				current.Value = "ON"
+				res.Assignments = append(res.Assignments, current)
+				current = nil
+			}
 		}
-	} else {
-		col = ctx.STRING_LITERAL().GetText()
-		col = WithTrimQuote(col)
-		col = WithReplacer(col, "\t", "", "\n", "", "\r", "")
 	}
-	slog.Debug("VisitIndexColumnName", "index column name", col)
-	return col
+	for _, assignment := range res.Assignments {
+		v.assign(assignment)
+	}
+	return &res
 }
 
-func (v *Visitor) VisitIndexColumnNames(ctx *IndexColumnNamesContext) interface{} {
-	var cols []string
-	for _, col := range ctx.AllIndexColumnName() {
-		if indexCtx, ok := col.(*IndexColumnNameContext); ok {
-			indexColName := v.VisitIndexColumnName(indexCtx)
-			if val, ok := indexColName.(string); ok {
-				cols = append(cols, val)
+// VisitVariableClause return *VariableAssignment without value
+func (v *Visitor) VisitVariableClause(ctx *VariableClauseContext) interface{} {
+	var res VariableAssignment
+	switch {
+	case ctx.LOCAL_ID() != nil:
+		res.Scope = VariableUser
+		res.Name = WithTrimQuote(strings.TrimPrefix(ctx.LOCAL_ID().GetText(), "@"))
+	case ctx.GLOBAL_ID() != nil:
+		res.Scope = VariableSession
+		res.Name = strings.TrimPrefix(ctx.GLOBAL_ID().GetText(), "@@")
+		if i := strings.Index(res.Name, "."); i > 0 {
+			switch strings.ToUpper(res.Name[:i]) {
+			case "GLOBAL":
+				res.Scope = VariableGlobal
+				res.Name = res.Name[i+1:]
+			case "SESSION", "LOCAL":
+				res.Name = res.Name[i+1:]
 			}
 		}
+		res.Name = WithTrimQuote(res.Name)
+	default:
+		res.Scope = VariableSession
+		if ctx.GLOBAL() != nil {
+			res.Scope = VariableGlobal
+		}
+		if uu, ok := ctx.Uid().(*UidContext); ok {
+			res.Name, _ = v.VisitUid(uu).(string)
+		}
 	}
-	slog.Debug("VisitIndexColumnNames", "index column names", cols)
-	return cols
+	return &res
 }
 
-// sqlstatement start
+func (v *Visitor) VisitSetNames(ctx *SetNamesContext) interface{} {
+	var res SetNames
+	if nameCtx := ctx.CharsetName(); nameCtx != nil {
+		res.Charset = WithTrimQuote(nameCtx.GetText())
+	} else {
+		res.Charset = "DEFAULT"
+	}
+	if nameCtx := ctx.CollationName(); nameCtx != nil {
+		res.Collation = WithTrimQuote(nameCtx.GetText())
+	}
 
-func (v *Visitor) VisitRoot(ctx *RootContext) interface{} {
-	if val, ok := ctx.SqlStatements().(*SqlStatementsContext); ok && val != nil {
-		return v.VisitSqlStatements(val)
+	session := v.currentSession()
+	session.CharacterSetClient = res.Charset
+	session.CollationConnection = res.Collation
+	v.session = session
+	return &res
+}
+
+func (v *Visitor) VisitSetCharset(ctx *SetCharsetContext) interface{} {
+	var res SetCharset
+	if nameCtx := ctx.CharsetName(); nameCtx != nil {
+		res.Charset = WithTrimQuote(nameCtx.GetText())
+	} else {
+		res.Charset = "DEFAULT"
 	}
-	return nil
+
+	session := v.currentSession()
+	session.CharacterSetClient = res.Charset
+	v.session = session
+	return &res
 }
 
-func (v *Visitor) VisitSqlStatements(ctx *SqlStatementsContext) interface{} {
-	var createTables []*CreateTable
-	for _, val := range ctx.AllSqlStatement() {
-		if sqlStatementCtx, ok := val.(*SqlStatementContext); ok && sqlStatementCtx != nil {
-			res := v.VisitSqlStatement(sqlStatementCtx)
-			if res != nil {
-				if data, ok := res.(*CreateTable); ok {
-					createTables = append(createTables, data)
-				}
-			}
+// currentSession return a copy of the session to be changed
+func (v *Visitor) currentSession() *Session {
+	var res Session
+	if v.session != nil {
+		res = *v.session
+	}
+	return &res
+}
+
+// assign keep the user variable and apply the session variable which change the parsing semantics
+func (v *Visitor) assign(assignment *VariableAssignment) {
+	value := v.resolveVariable(assignment.Value)
+	if assignment.Scope == VariableUser {
+		if v.userVariables == nil {
+			v.userVariables = make(map[string]string)
 		}
+		v.userVariables[strings.ToLower(assignment.Name)] = value
+		return
+	}
+	if assignment.Scope == VariableGlobal {
+		return
+	}
+	switch strings.ToLower(assignment.Name) {
+	case "sql_mode":
+		session := v.currentSession()
+		session.SQLMode = strings.ToUpper(value)
+		v.session = session
+	case "character_set_client":
+		session := v.currentSession()
+		session.CharacterSetClient = value
+		v.session = session
+	case "collation_connection":
+		session := v.currentSession()
+		session.CollationConnection = value
+		v.session = session
+	case "character_set_database":
+		session := v.currentSession()
+		session.CharacterSetDatabase = value
+		v.session = session
+	case "collation_database":
+		session := v.currentSession()
+		session.CollationDatabase = value
+		v.session = session
+	case "character_set_server":
+		session := v.currentSession()
+		session.CharacterSetServer = value
+		v.session = session
+	case "collation_server":
+		session := v.currentSession()
+		session.CollationServer = value
+		v.session = session
 	}
-	return createTables
 }
 
-func (v *Visitor) VisitSqlStatement(ctx *SqlStatementContext) interface{} {
-	if tmp := ctx.DdlStatement(); tmp != nil {
-		return v.VisitDdlStatement(tmp.(*DdlStatementContext))
+// resolveVariable return the value of literal, @var and the tracked @@var
+func (v *Visitor) resolveVariable(text string) string {
+	switch {
+	case strings.HasPrefix(text, "@@"):
+		name := strings.ToLower(strings.TrimPrefix(text, "@@"))
+		name = strings.TrimPrefix(strings.TrimPrefix(name, "session."), "local.")
+		var session Session
+		if v.session != nil {
+			session = *v.session
+		}
+		switch name {
+		case "sql_mode":
+			return session.SQLMode
+		case "character_set_client":
+			return session.CharacterSetClient
+		case "collation_connection":
+			return session.CollationConnection
+		case "character_set_database":
+			return session.CharacterSetDatabase
+		case "collation_database":
+			return session.CollationDatabase
+		case "character_set_server":
+			return session.CharacterSetServer
+		case "collation_server":
+			return session.CollationServer
+		}
+		return ""
+	case strings.HasPrefix(text, "@"):
+		name := strings.ToLower(WithTrimQuote(strings.TrimPrefix(text, "@")))
+		return v.userVariables[name]
+	case strings.EqualFold(text, "DEFAULT"):
+		return ""
 	}
-	if tmp := ctx.DmlStatement(); tmp != nil {
-		slog.Warn("unsupport VisitDmlStatement")
+	return WithTrimQuote(text)
+}
+
+// --- set end
+
+// --- database start
+
+func (v *Visitor) VisitCreateDatabase(ctx *CreateDatabaseContext) interface{} {
+	var res CreateDatabase
+	var db Database
+	res.IfNotExists = ctx.IfNotExists() != nil
+	if uu, ok := ctx.Uid().(*UidContext); ok {
+		db.Name, _ = v.VisitUid(uu).(string)
 	}
-	if tmp := ctx.TransactionStatement(); tmp != nil {
-		slog.Warn("unsupport VisitTransactionStatement")
+	for _, op := range ctx.AllCreateDatabaseOption() {
+		if opCtx, ok := op.(*CreateDatabaseOptionContext); ok {
+			v.visitCreateDatabaseOption(opCtx, &db)
+		}
 	}
-	if tmp := ctx.ReplicationStatement(); tmp != nil {
-		slog.Warn("unsupport VisitReplicationStatement")
+	res.Database = &db
+	return &res
+}
+
+func (v *Visitor) VisitAlterSimpleDatabase(ctx *AlterSimpleDatabaseContext) interface{} {
+	var res AlterDatabase
+	var db Database
+	db.Name = v.database
+	if uu, ok := ctx.Uid().(*UidContext); ok {
+		db.Name, _ = v.VisitUid(uu).(string)
 	}
-	if tmp := ctx.PreparedStatement(); tmp != nil {
-		slog.Warn("unsupport VisitPreparedStatement")
+	for _, op := range ctx.AllCreateDatabaseOption() {
+		if opCtx, ok := op.(*CreateDatabaseOptionContext); ok {
+			v.visitCreateDatabaseOption(opCtx, &db)
+		}
+	}
+	res.Database = &db
+	return &res
+}
+
+// visitCreateDatabaseOption set the option into db
+func (v *Visitor) visitCreateDatabaseOption(ctx *CreateDatabaseOptionContext, db *Database) {
+	switch {
+	case ctx.CharSet() != nil:
+		if nameCtx := ctx.CharsetName(); nameCtx != nil {
+			db.Charset = WithTrimQuote(nameCtx.GetText())
+		} else {
+			db.Charset = "DEFAULT"
+		}
+	case ctx.COLLATE() != nil:
+		if nameCtx := ctx.CollationName(); nameCtx != nil {
+			db.Collation = WithTrimQuote(nameCtx.GetText())
+		}
+	case ctx.ENCRYPTION() != nil:
+		if str := ctx.STRING_LITERAL(); str != nil {
+			db.Encryption = WithTrimQuote(str.GetText())
+		}
+	case ctx.READ() != nil:
+		switch {
+		case ctx.ZERO_DECIMAL() != nil:
+			db.ReadOnly = "0"
+		case ctx.ONE_DECIMAL() != nil:
+			db.ReadOnly = "1"
+		default:
+			db.ReadOnly = "DEFAULT"
+		}
 	}
-	if tmp := ctx.AdministrationStatement(); tmp != nil {
-		slog.Warn("unsupport AdministrationStatement")
+}
+
+func (v *Visitor) VisitDropDatabase(ctx *DropDatabaseContext) interface{} {
+	var res DropDatabase
+	res.IfExists = ctx.IfExists() != nil
+	if uu, ok := ctx.Uid().(*UidContext); ok {
+		res.Name, _ = v.VisitUid(uu).(string)
 	}
-	if tmp := ctx.UtilityStatement(); tmp != nil {
-		slog.Warn("unsupport UtilityStatement")
+	// dropping the current database leaves no database selected
+	if res.Name == v.database {
+		v.database = ""
 	}
-	return nil
+	return &res
 }
 
-func (v *Visitor) VisitDdlStatement(ctx *DdlStatementContext) interface{} {
-	if ctx.CreateTable() != nil {
-		return v.VisitCreateTable(ctx.CreateTable())
+// VisitUseStatement change the current database for the following statements
+func (v *Visitor) VisitUseStatement(ctx *UseStatementContext) interface{} {
+	var res UseDatabase
+	if uu, ok := ctx.Uid().(*UidContext); ok {
+		res.Name, _ = v.VisitUid(uu).(string)
 	}
-	return nil
+	v.database = res.Name
+	return &res
 }
 
-// sqlstatement end
+// --- database end
 
 // --- createTable start
 
@@ -125,7 +1550,7 @@ func (v *Visitor) VisitCreateTable(ctx ICreateTableContext) interface{} {
 		slog.Debug("CreateTable  ColumnCreateTable")
 		return v.VisitColumnCreateTable(tx)
 	default:
-		slog.Warn("unknown CreateTableContext", tx)
+		slog.Warn("unknown CreateTableContext", "ctx", tx)
 		return nil
 	}
 }
@@ -137,6 +1562,18 @@ func (v *Visitor) VisitColumnCreateTable(ctx *ColumnCreateTableContext) interfac
 	tblName = WithTrimQuote(tblName)
 	tblName = WithReplacer(tblName, "\t", "", "\r", "", "\n", "")
 	res.Name = tblName
+	res.IfNotExists = ctx.IfNotExists() != nil
+	res.Options = v.visitTableOptions(ctx.AllTableOption())
+	if partCtx, ok := ctx.PartitionDefinitions().(*PartitionDefinitionsContext); ok {
+		res.Partition, _ = v.VisitPartitionDefinitions(partCtx).(*Partitioning)
+	}
+	res.Session = v.session
+	if tblCtx, ok := ctx.TableName().(*TableNameContext); ok {
+		if name, ok := v.VisitTableName(tblCtx).(*TableName); ok {
+			res.Schema = name.Schema
+			res.Name = name.Name
+		}
+	}
 
 	slog.Debug("VisitColumnCreateTable", "tableName", tblName)
 
@@ -221,14 +1658,422 @@ func (v *Visitor) VisitCreateDefinition(ctx ICreateDefinitionContext) interface{
 	return nil
 }
 
+// VisitIndexColumnDefinition return *TableConstraint of the KEY, INDEX, FULLTEXT or SPATIAL index
 func (v *Visitor) VisitIndexColumnDefinition(ctx IIndexColumnDefinitionContext) interface{} {
-	// TODO:
-	slog.Warn("unsupport VisitIndexColumnDefinition")
-	return nil
+	var res TableConstraint
+	switch tx := ctx.(type) {
+	case *SimpleIndexDeclarationContext:
+		res.Name = v.visitUid(tx.Uid())
+		if colsCtx, ok := tx.IndexColumnNames().(*IndexColumnNamesContext); ok {
+			res.ColumnIndex, _ = v.VisitIndexColumnNames(colsCtx).([]string)
+		}
+	case *SpecialIndexDeclarationContext:
+		res.Name = v.visitUid(tx.Uid())
+		if tx.FULLTEXT() != nil {
+			res.IndexKind = "FULLTEXT"
+		} else {
+			res.IndexKind = "SPATIAL"
+		}
+		if colsCtx, ok := tx.IndexColumnNames().(*IndexColumnNamesContext); ok {
+			res.ColumnIndex, _ = v.VisitIndexColumnNames(colsCtx).([]string)
+		}
+	default:
+		slog.Warn("unknown IndexColumnDefinitionContext", "ctx", tx)
+		return nil
+	}
+	return &res
+}
+
+// VisitPartitionDefinitions return *Partitioning, the subpartitions are only kept in the source text
+func (v *Visitor) VisitPartitionDefinitions(ctx *PartitionDefinitionsContext) interface{} {
+	res := Partitioning{Source: sourceText(ctx.GetStart(), ctx.GetStop())}
+	switch tx := ctx.PartitionFunctionDefinition().(type) {
+	case *PartitionFunctionHashContext:
+		res.Type = "HASH"
+		if tx.LINEAR() != nil {
+			res.Type = "LINEAR HASH"
+		}
+		res.Expression = sourceText(tx.Expression().GetStart(), tx.Expression().GetStop())
+	case *PartitionFunctionKeyContext:
+		res.Type = "KEY"
+		if tx.LINEAR() != nil {
+			res.Type = "LINEAR KEY"
+		}
+		if listCtx, ok := tx.UidList().(*UidListContext); ok {
+			res.Columns, _ = v.VisitUidList(listCtx).([]string)
+		}
+	case *PartitionFunctionRangeContext:
+		res.Type = "RANGE"
+		if expr := tx.Expression(); expr != nil {
+			res.Expression = sourceText(expr.GetStart(), expr.GetStop())
+		}
+		if listCtx, ok := tx.UidList().(*UidListContext); ok {
+			res.Columns, _ = v.VisitUidList(listCtx).([]string)
+		}
+	case *PartitionFunctionListContext:
+		res.Type = "LIST"
+		if expr := tx.Expression(); expr != nil {
+			res.Expression = sourceText(expr.GetStart(), expr.GetStop())
+		}
+		if listCtx, ok := tx.UidList().(*UidListContext); ok {
+			res.Columns, _ = v.VisitUidList(listCtx).([]string)
+		}
+	}
+	if count := ctx.GetCount(); count != nil {
+		res.Count, _ = strconv.Atoi(count.GetText())
+	}
+	for _, def := range ctx.AllPartitionDefinition() {
+		res.Partitions = append(res.Partitions, v.visitPartitionDefinition(def))
+	}
+	return &res
+}
+
+func (v *Visitor) visitPartitionDefinition(ctx IPartitionDefinitionContext) *Partition {
+	res := Partition{Source: sourceText(ctx.GetStart(), ctx.GetStop())}
+	for _, child := range ctx.GetChildren() {
+		if uu, ok := child.(*UidContext); ok {
+			res.Name, _ = v.VisitUid(uu).(string)
+			break
+		}
+	}
+	return &res
+}
+
+// visitTableOptions return nil if there is no option
+func (v *Visitor) visitTableOptions(options []ITableOptionContext) *TableOptions {
+	if len(options) == 0 {
+		return nil
+	}
+	var res TableOptions
+	for _, op := range options {
+		switch tx := op.(type) {
+		case *TableOptionEngineContext:
+			if nameCtx := tx.EngineName(); nameCtx != nil {
+				res.Engine = WithTrimQuote(nameCtx.GetText())
+			}
+		case *TableOptionCharsetContext:
+			if nameCtx := tx.CharsetName(); nameCtx != nil {
+				res.Charset = WithTrimQuote(nameCtx.GetText())
+			} else {
+				res.Charset = "DEFAULT"
+			}
+		case *TableOptionCollateContext:
+			if nameCtx := tx.CollationName(); nameCtx != nil {
+				res.Collation = WithTrimQuote(nameCtx.GetText())
+			}
+		case *TableOptionCommentContext:
+			res.Comment = WithUnquote(tx.STRING_LITERAL().GetText())
+		case *TableOptionAutoIncrementContext:
+			if num := tx.DecimalLiteral(); num != nil {
+				res.AutoIncrement = num.GetText()
+			}
+		case *TableOptionRowFormatContext:
+			if format := tx.GetRowFormat(); format != nil {
+				res.RowFormat = strings.ToUpper(format.GetText())
+			}
+		default:
+			slog.Debug("visitTableOptions", "unsupport table option", op.GetText())
+		}
+	}
+	return &res
 }
 
 // --- createTable end
 
+// --- alterTable start
+
+func (v *Visitor) VisitAlterTable(ctx *AlterTableContext) interface{} {
+	var res AlterTable
+	if tblCtx, ok := ctx.TableName().(*TableNameContext); ok {
+		res.Table, _ = v.VisitTableName(tblCtx).(*TableName)
+	}
+	for _, spec := range ctx.AllAlterSpecification() {
+		res.Specs = append(res.Specs, v.visitAlterSpecification(spec)...)
+	}
+	if part, ok := ctx.PartitionDefinitions().(*PartitionDefinitionsContext); ok {
+		res.Specs = append(res.Specs, &AlterSpecification{
+			Action:    AlterPartitionBy,
+			Source:    sourceText(part.GetStart(), part.GetStop()),
+			Partition: v.VisitPartitionDefinitions(part).(*Partitioning),
+		})
+	}
+	return &res
+}
+
+// visitAlterSpecification return more than one specification for adding columns or definitions
+func (v *Visitor) visitAlterSpecification(ctx IAlterSpecificationContext) []*AlterSpecification {
+	res := AlterSpecification{
+		Action: AlterOther,
+		Source: sourceText(ctx.GetStart(), ctx.GetStop()),
+	}
+	switch tx := ctx.(type) {
+	case *AlterByTableOptionContext:
+		res.Action = AlterTableOptions
+		res.Options = v.visitTableOptions(tx.AllTableOption())
+	case *AlterByAddColumnContext:
+		res.Action = AlterAddColumn
+		uids := tx.AllUid()
+		res.Column = v.visitColumn(uids[0], tx.ColumnDefinition())
+		res.First = tx.FIRST() != nil
+		if len(uids) > 1 {
+			res.After = v.visitUid(uids[1])
+		}
+	case *AlterByAddColumnsContext:
+		var specs []*AlterSpecification
+		defs := tx.AllColumnDefinition()
+		for i, uid := range tx.AllUid() {
+			specs = append(specs, &AlterSpecification{
+				Action: AlterAddColumn,
+				Source: res.Source,
+				Column: v.visitColumn(uid, defs[i]),
+			})
+		}
+		return specs
+	case *AlterByAddDefinitionsContext:
+		var specs []*AlterSpecification
+		for _, def := range tx.AllCreateDefinition() {
+			spec := &AlterSpecification{Source: res.Source}
+			switch r := v.VisitCreateDefinition(def).(type) {
+			case *ColumnDeclaration:
+				spec.Action = AlterAddColumn
+				spec.Column = &Column{Name: r.Name}
+				if r.ColumnDefinition != nil {
+					spec.Column.DataType = r.ColumnDefinition.DataType
+					spec.Column.Constraint = r.ColumnDefinition.ColumnConstraint
+				}
+			case *TableConstraint:
+				spec.Action = AlterAddConstraint
+				spec.Constraint = r
+			default:
+				continue
+			}
+			specs = append(specs, spec)
+		}
+		return specs
+	case *AlterByAddIndexContext:
+		res.Action = AlterAddConstraint
+		res.Constraint = &TableConstraint{Name: v.visitUid(tx.Uid())}
+		if colsCtx, ok := tx.IndexColumnNames().(*IndexColumnNamesContext); ok {
+			res.Constraint.ColumnIndex, _ = v.VisitIndexColumnNames(colsCtx).([]string)
+		}
+	case *AlterByAddSpecialIndexContext:
+		res.Action = AlterAddConstraint
+		res.Constraint = &TableConstraint{Name: v.visitUid(tx.Uid()), IndexKind: "SPATIAL"}
+		if tx.FULLTEXT() != nil {
+			res.Constraint.IndexKind = "FULLTEXT"
+		}
+		if colsCtx, ok := tx.IndexColumnNames().(*IndexColumnNamesContext); ok {
+			res.Constraint.ColumnIndex, _ = v.VisitIndexColumnNames(colsCtx).([]string)
+		}
+	case *AlterByAddPrimaryKeyContext:
+		res.Action = AlterAddConstraint
+		res.Constraint = &TableConstraint{}
+		if colsCtx, ok := tx.IndexColumnNames().(*IndexColumnNamesContext); ok {
+			res.Constraint.ColumnPrimaryKey, _ = v.VisitIndexColumnNames(colsCtx).([]string)
+		}
+	case *AlterByAddUniqueKeyContext:
+		res.Action = AlterAddConstraint
+		res.Constraint = &TableConstraint{Name: v.visitUid(tx.GetName())}
+		if name := v.visitUid(tx.GetIndexName()); name != "" {
+			res.Constraint.Name = name
+		}
+		if colsCtx, ok := tx.IndexColumnNames().(*IndexColumnNamesContext); ok {
+			res.Constraint.ColumnUniqueKey, _ = v.VisitIndexColumnNames(colsCtx).([]string)
+		}
+	case *AlterByAddForeignKeyContext:
+		res.Action = AlterAddConstraint
+		res.Constraint = &TableConstraint{Name: v.visitUid(tx.GetName())}
+		if colsCtx, ok := tx.IndexColumnNames().(*IndexColumnNamesContext); ok {
+			res.Constraint.ColumnForeignKey, _ = v.VisitIndexColumnNames(colsCtx).([]string)
+		}
+		if refCtx, ok := tx.ReferenceDefinition().(*ReferenceDefinitionContext); ok {
+			res.Constraint.Reference, _ = v.VisitReferenceDefinition(refCtx).(*Reference)
+		}
+	case *AlterByAddCheckTableConstraintContext:
+		res.Action = AlterAddConstraint
+		res.Constraint = &TableConstraint{Name: v.visitUid(tx.GetName())}
+		if expr := tx.Expression(); expr != nil {
+			res.Constraint.Check = sourceText(expr.GetStart(), expr.GetStop())
+		}
+	case *AlterBySetAlgorithmContext:
+		res.Action = AlterAlgorithm
+		res.Value = strings.ToUpper(tx.GetAlgType().GetText())
+	case *AlterByLockContext:
+		res.Action = AlterLock
+		res.Value = strings.ToUpper(tx.GetLockType().GetText())
+	case *AlterByChangeDefaultContext:
+		res.Name = v.visitUid(tx.Uid())
+		if def := tx.DefaultValue(); def != nil {
+			res.Action = AlterSetDefault
+			res.Default = v.visitDefaultValue(def)
+		} else {
+			res.Action = AlterDropDefault
+		}
+	case *AlterByAlterColumnDefaultContext:
+		res.Name = v.visitUid(tx.Uid())
+		switch {
+		case tx.DROP() != nil:
+			res.Action = AlterDropDefault
+		case tx.StringLiteral() != nil:
+			res.Action = AlterSetDefault
+			res.Default = &DefaultValue{Value: stringLiteralValue(tx.StringLiteral()), Is: true, Kind: DefaultString}
+		case tx.Expression() != nil:
+			res.Action = AlterSetDefault
+			res.Default = &DefaultValue{Value: "(" + sourceText(tx.Expression().GetStart(), tx.Expression().GetStop()) + ")", Is: true, Kind: DefaultExpression}
+		}
+	case *AlterByChangeColumnContext:
+		res.Action = AlterChangeColumn
+		res.Name = v.visitUid(tx.GetOldColumn())
+		res.NewName = v.visitUid(tx.GetNewColumn())
+		res.Column = v.visitColumn(tx.GetNewColumn(), tx.ColumnDefinition())
+		res.First = tx.FIRST() != nil
+		res.After = v.visitUid(tx.GetAfterColumn())
+	case *AlterByRenameColumnContext:
+		res.Action = AlterRenameColumn
+		res.Name = v.visitUid(tx.GetOldColumn())
+		res.NewName = v.visitUid(tx.GetNewColumn())
+	case *AlterByModifyColumnContext:
+		res.Action = AlterModifyColumn
+		uids := tx.AllUid()
+		res.Name = v.visitUid(uids[0])
+		res.Column = v.visitColumn(uids[0], tx.ColumnDefinition())
+		res.First = tx.FIRST() != nil
+		if len(uids) > 1 {
+			res.After = v.visitUid(uids[1])
+		}
+	case *AlterByDropColumnContext:
+		res.Action = AlterDropColumn
+		res.Name = v.visitUid(tx.Uid())
+	case *AlterByDropConstraintCheckContext:
+		res.Action = AlterDropConstraint
+		if tx.CHECK() != nil {
+			res.Action = AlterDropCheck
+		}
+		res.Name = v.visitUid(tx.Uid())
+	case *AlterByDropPrimaryKeyContext:
+		res.Action = AlterDropPrimaryKey
+	case *AlterByDropIndexContext:
+		res.Action = AlterDropIndex
+		res.Name = v.visitUid(tx.Uid())
+	case *AlterByDropForeignKeyContext:
+		res.Action = AlterDropForeignKey
+		res.Name = v.visitUid(tx.Uid())
+	case *AlterByRenameIndexContext:
+		res.Action = AlterRenameIndex
+		res.Name = v.visitUid(tx.Uid(0))
+		res.NewName = v.visitUid(tx.Uid(1))
+	case *AlterByAlterIndexVisibilityContext:
+		res.Action = AlterIndexVisibility
+		res.Name = v.visitUid(tx.Uid())
+		res.Value = "VISIBLE"
+		if tx.INVISIBLE() != nil {
+			res.Value = "INVISIBLE"
+		}
+	case *AlterByRenameContext:
+		res.Action = AlterRenameTable
+		if idCtx, ok := tx.FullId().(*FullIdContext); ok {
+			res.RenameTo, _ = v.VisitFullId(idCtx).(*TableName)
+		} else if name := v.visitUid(tx.Uid()); name != "" {
+			res.RenameTo = &TableName{Schema: v.database, Name: name}
+		}
+	case *AlterByConvertCharsetContext:
+		res.Action = AlterConvertCharset
+		res.Options = &TableOptions{Charset: WithTrimQuote(tx.CharsetName().GetText())}
+		if nameCtx := tx.CollationName(); nameCtx != nil {
+			res.Options.Collation = WithTrimQuote(nameCtx.GetText())
+		}
+	case *AlterByDefaultCharsetContext:
+		res.Action = AlterTableOptions
+		res.Options = &TableOptions{Charset: WithTrimQuote(tx.CharsetName().GetText())}
+		if nameCtx := tx.CollationName(); nameCtx != nil {
+			res.Options.Collation = WithTrimQuote(nameCtx.GetText())
+		}
+	case *AlterPartitionContext:
+		switch px := tx.AlterPartitionSpecification().(type) {
+		case *AlterByAddPartitionContext:
+			res.Action = AlterAddPartition
+			res.Partition = &Partitioning{Source: res.Source}
+			for _, def := range px.AllPartitionDefinition() {
+				res.Partition.Partitions = append(res.Partition.Partitions, v.visitPartitionDefinition(def))
+			}
+		case *AlterByDropPartitionContext:
+			var specs []*AlterSpecification
+			if listCtx, ok := px.UidList().(*UidListContext); ok {
+				names, _ := v.VisitUidList(listCtx).([]string)
+				for _, name := range names {
+					specs = append(specs, &AlterSpecification{Action: AlterDropPartition, Source: res.Source, Name: name})
+				}
+			}
+			return specs
+		case *AlterByRemovePartitioningContext:
+			res.Action = AlterRemovePartition
+		}
+	default:
+		slog.Debug("visitAlterSpecification", "unsupport alter specification", res.Source)
+	}
+	return []*AlterSpecification{&res}
+}
+
+// visitColumn return the column named uid with the definition
+func (v *Visitor) visitColumn(uid IUidContext, ctx IColumnDefinitionContext) *Column {
+	res := Column{Name: v.visitUid(uid)}
+	if defCtx, ok := ctx.(*ColumnDefinitionContext); ok {
+		if def, ok := v.VisitColumnDefinition(defCtx).(*ColumnDefinition); ok {
+			res.DataType = def.DataType
+			res.Constraint = def.ColumnConstraint
+		}
+	}
+	return &res
+}
+
+func (v *Visitor) VisitCreateIndex(ctx *CreateIndexContext) interface{} {
+	var res CreateIndex
+	if tblCtx, ok := ctx.TableName().(*TableNameContext); ok {
+		res.Table, _ = v.VisitTableName(tblCtx).(*TableName)
+	}
+	var cols []string
+	if colsCtx, ok := ctx.IndexColumnNames().(*IndexColumnNamesContext); ok {
+		cols, _ = v.VisitIndexColumnNames(colsCtx).([]string)
+	}
+	res.Constraint = &TableConstraint{Name: v.visitUid(ctx.Uid())}
+	switch {
+	case ctx.UNIQUE() != nil:
+		res.Constraint.ColumnUniqueKey = cols
+	case ctx.FULLTEXT() != nil:
+		res.Constraint.IndexKind = "FULLTEXT"
+		res.Constraint.ColumnIndex = cols
+	case ctx.SPATIAL() != nil:
+		res.Constraint.IndexKind = "SPATIAL"
+		res.Constraint.ColumnIndex = cols
+	default:
+		res.Constraint.ColumnIndex = cols
+	}
+	if alg := ctx.GetAlgType(); alg != nil {
+		res.Algorithm = strings.ToUpper(alg.GetText())
+	}
+	if lock := ctx.GetLockType(); lock != nil {
+		res.Lock = strings.ToUpper(lock.GetText())
+	}
+	return &res
+}
+
+func (v *Visitor) VisitDropIndex(ctx *DropIndexContext) interface{} {
+	var res DropIndex
+	res.Name = v.visitUid(ctx.Uid())
+	if tblCtx, ok := ctx.TableName().(*TableNameContext); ok {
+		res.Table, _ = v.VisitTableName(tblCtx).(*TableName)
+	}
+	if alg := ctx.GetAlgType(); alg != nil {
+		res.Algorithm = strings.ToUpper(alg.GetText())
+	}
+	if lock := ctx.GetLockType(); lock != nil {
+		res.Lock = strings.ToUpper(lock.GetText())
+	}
+	return &res
+}
+
+// --- alterTable end
+
 // --- tableConstraint start
 
 // VisitTableConstraint
@@ -246,16 +2091,34 @@ func (v *Visitor) VisitTableConstraint(ctx ITableConstraintContext) *TableConstr
 		}
 	case *UniqueKeyTableConstraintContext:
 		slog.Debug("VisitTableConstraint", "ctx", "*UniqueKeyTableConstraintContext")
-		tmp = v.VisitUniqueKeyTableConstraint(ctx.(*UniqueKeyTableConstraintContext))
+		tx := ctx.(*UniqueKeyTableConstraintContext)
+		tmp = v.VisitUniqueKeyTableConstraint(tx)
 		if val, ok := tmp.([]string); ok {
 			res.ColumnUniqueKey = val
 		}
+		// the index name takes precedence over the constraint name
+		res.Name = v.visitUid(tx.GetName())
+		if name := v.visitUid(tx.GetIndex()); name != "" {
+			res.Name = name
+		}
 	case *ForeignKeyTableConstraintContext:
 		slog.Debug("VisitTableConstraint", "ctx", "*ForeignKeyTableConstraintContext")
-		tmp = v.VisitForeignKeyTableConstraint(ctx.(*ForeignKeyTableConstraintContext))
+		tx := ctx.(*ForeignKeyTableConstraintContext)
+		tmp = v.VisitForeignKeyTableConstraint(tx)
 		if val, ok := tmp.([]string); ok {
 			res.ColumnForeignKey = val
 		}
+		res.Name = v.visitUid(tx.GetName())
+		if refCtx, ok := tx.ReferenceDefinition().(*ReferenceDefinitionContext); ok {
+			res.Reference, _ = v.VisitReferenceDefinition(refCtx).(*Reference)
+		}
+	case *CheckTableConstraintContext:
+		slog.Debug("VisitTableConstraint", "ctx", "*CheckTableConstraintContext")
+		tx := ctx.(*CheckTableConstraintContext)
+		res.Name = v.visitUid(tx.GetName())
+		if expr := tx.Expression(); expr != nil {
+			res.Check = sourceText(expr.GetStart(), expr.GetStop())
+		}
 	}
 	return &res
 }
@@ -312,6 +2175,35 @@ func (v *Visitor) VisitForeignKeyTableConstraint(ctx *ForeignKeyTableConstraintC
 	return res
 }
 
+func (v *Visitor) VisitReferenceDefinition(ctx *ReferenceDefinitionContext) interface{} {
+	var res Reference
+	if tblCtx, ok := ctx.TableName().(*TableNameContext); ok {
+		res.Table, _ = v.VisitTableName(tblCtx).(*TableName)
+	}
+	if colsCtx, ok := ctx.IndexColumnNames().(*IndexColumnNamesContext); ok {
+		res.Columns, _ = v.VisitIndexColumnNames(colsCtx).([]string)
+	}
+	if action := ctx.ReferenceAction(); action != nil {
+		res.OnDelete = referenceControlType(action.GetOnDelete())
+		res.OnUpdate = referenceControlType(action.GetOnUpdate())
+	}
+	return &res
+}
+
+// referenceControlType return the action like CASCADE or SET NULL, empty if ctx is nil
+func referenceControlType(ctx IReferenceControlTypeContext) string {
+	if ctx == nil {
+		return ""
+	}
+	var words []string
+	for _, child := range ctx.GetChildren() {
+		if node, ok := child.(antlr.TerminalNode); ok {
+			words = append(words, strings.ToUpper(node.GetText()))
+		}
+	}
+	return strings.Join(words, " ")
+}
+
 // --- tableConstraint end
 
 // --- columnDefinition start
@@ -329,9 +2221,16 @@ func (v *Visitor) VisitColumnDefinition(ctx *ColumnDefinitionContext) interface{
 		case *DefaultColumnConstraintContext:
 			slog.Debug("VisitColumnDefinition", "ColumnConstraint", "Default")
 			constraint.DefaultValue = v.VisitDefaultColumnConstraint(tx).(*DefaultValue)
+			if onUpdate := defaultOnUpdate(tx.DefaultValue()); onUpdate != "" {
+				constraint.OnUpdate = onUpdate
+			}
 		case *AutoIncrementColumnConstraintContext:
 			slog.Debug("VisitColumnDefinition", "ColumnConstraint", "AutoIncrement")
-			constraint.AutoIncrement = v.VisitAutoIncrementColumnConstraint(tx).(bool)
+			if v.VisitAutoIncrementColumnConstraint(tx).(bool) {
+				constraint.AutoIncrement = true
+			} else if ts := tx.CurrentTimestamp(); ts != nil {
+				constraint.OnUpdate = sourceText(ts.GetStart(), ts.GetStop())
+			}
 		case *PrimaryKeyColumnConstraintContext:
 			slog.Debug("VisitColumnDefinition", "ColumnConstraint", "PrimaryKey")
 			ret := v.VisitPrimaryKeyColumnConstraint(tx)
@@ -360,11 +2259,15 @@ func (v *Visitor) VisitColumnDefinition(ctx *ColumnDefinitionContext) interface{
 		case *SerialDefaultColumnConstraintContext:
 			slog.Warn("unsupport SerialDefaultColumnConstraint")
 		case *GeneratedColumnConstraintContext:
-			slog.Warn("unsupport GeneratedColumnConstraint")
+			slog.Debug("VisitColumnDefinition", "ColumnConstraint", "Generated")
+			constraint.Generated = sourceText(tx.Expression().GetStart(), tx.Expression().GetStop())
+			constraint.Stored = tx.STORED() != nil
 		case *FormatColumnConstraintContext:
 			slog.Warn("unsupport FormatColumnConstraint")
 		case *CollateColumnConstraintContext:
-			slog.Warn("unsupport CollateColumnConstraint")
+			if nameCtx := tx.CollationName(); nameCtx != nil {
+				definition.DataType.CollationName = WithTrimQuote(nameCtx.GetText())
+			}
 		case *CheckColumnConstraintContext:
 			slog.Warn("unsupport CheckColumnConstraint")
 		}
@@ -385,24 +2288,97 @@ func (v *Visitor) VisitNullNotnull(ctx *NullNotnullContext) interface{} {
 }
 
 func (v *Visitor) VisitDefaultColumnConstraint(ctx *DefaultColumnConstraintContext) interface{} {
+	return v.visitDefaultValue(ctx.DefaultValue())
+}
+
+func (v *Visitor) visitDefaultValue(ctx IDefaultValueContext) *DefaultValue {
 	res := DefaultValue{}
-	text := ctx.DefaultValue().GetText()
-	text = WithTrimQuote(text)
-	text = WithReplacer(text, "\r", "", "\t", "", "\n", "")
-	if strings.HasPrefix(strings.ToUpper(text), "NULL") {
+	tx, ok := ctx.(*DefaultValueContext)
+	if !ok || tx.NULL_LITERAL() != nil {
 		// default NULL
 		return &res
 	}
-	res.Value = text
 	res.Is = true
+	res.Kind = DefaultExpression
+	res.Value = sourceText(tx.GetStart(), tx.GetStop())
+	switch {
+	case tx.Constant() != nil:
+		c, ok := tx.Constant().(*ConstantContext)
+		if !ok {
+			break
+		}
+		sign := ""
+		if tx.UnaryOperator() != nil {
+			sign = tx.UnaryOperator().GetText()
+		}
+		switch {
+		case c.NULL_LITERAL() != nil || c.NULL_SPEC_LITERAL() != nil:
+			if c.NOT() == nil && sign == "" {
+				return &DefaultValue{}
+			}
+		case c.StringLiteral() != nil:
+			res.Kind = DefaultString
+			res.Value = stringLiteralValue(c.StringLiteral())
+			if sign != "" {
+				// e.g. -'1' is a number expression
+				res.Kind = DefaultExpression
+				res.Value = sourceText(tx.GetStart(), tx.GetStop())
+			}
+		case c.BooleanLiteral() != nil:
+			// TRUE and FALSE are 1 and 0
+			res.Kind = DefaultNumber
+			res.Value = "0"
+			if c.BooleanLiteral().TRUE() != nil {
+				res.Value = "1"
+			}
+		case c.BIT_STRING() != nil:
+			res.Kind = DefaultBit
+		case c.HexadecimalLiteral() != nil:
+			res.Kind = DefaultHex
+		default:
+			res.Kind = DefaultNumber
+			res.Value = sign + sourceText(c.GetStart(), c.GetStop())
+		}
+	case len(tx.AllCurrentTimestamp()) != 0:
+		// ON UPDATE is the OnUpdate of the column constraint
+		ts := tx.CurrentTimestamp(0)
+		res.Value = sourceText(ts.GetStart(), ts.GetStop())
+	}
 	return &res
 }
 
+// defaultOnUpdate return the ON UPDATE of DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+// empty if none
+func defaultOnUpdate(ctx IDefaultValueContext) string {
+	tx, ok := ctx.(*DefaultValueContext)
+	if !ok || tx.UPDATE() == nil || len(tx.AllCurrentTimestamp()) < 2 {
+		return ""
+	}
+	ts := tx.CurrentTimestamp(1)
+	return sourceText(ts.GetStart(), ts.GetStop())
+}
+
+// stringLiteralValue return the unquoted value of the string literal, the parts of 'a' 'b' are
+// concatenated, the charset introducer and COLLATE are dropped
+func stringLiteralValue(ctx IStringLiteralContext) string {
+	tx, ok := ctx.(*StringLiteralContext)
+	if !ok {
+		return WithUnquote(ctx.GetText())
+	}
+	var str strings.Builder
+	if national := tx.START_NATIONAL_STRING_LITERAL(); national != nil {
+		// N'abc'
+		str.WriteString(WithUnquote(national.GetText()[1:]))
+	}
+	for _, part := range tx.AllSTRING_LITERAL() {
+		str.WriteString(WithUnquote(part.GetText()))
+	}
+	return str.String()
+}
+
 func (v *Visitor) VisitAutoIncrementColumnConstraint(ctx *AutoIncrementColumnConstraintContext) interface{} {
-	// auto := ctx.AUTO_INCREMENT()
-	// fmt.Printf("auto: %#v\ntext: %#v\n", auto, auto.GetSymbol().GetText())
-	// When entering this function, it means that there is the AUTO_INCREMENT keyword, so it must be true
-	return true
+	// the alternative of ON UPDATE CURRENT_TIMESTAMP is not AUTO_INCREMENT
+	return ctx.AUTO_INCREMENT() != nil
 }
 
 func (v *Visitor) VisitPrimaryKeyColumnConstraint(ctx *PrimaryKeyColumnConstraintContext) interface{} {
@@ -424,8 +2400,8 @@ func (v *Visitor) VisitUniqueKeyColumnConstraint(ctx *UniqueKeyColumnConstraintC
 
 func (v *Visitor) VisitCommentColumnConstraint(ctx *CommentColumnConstraintContext) interface{} {
 	commentStr := ctx.STRING_LITERAL().GetText()
-	commentStr = WithTrimQuote(commentStr)
 	commentStr = WithReplacer(commentStr, "\r", "", "\n", "")
+	commentStr = WithUnquote(commentStr)
 	slog.Debug("VisitCommentColumnConstraint", "comment", commentStr)
 	return commentStr
 }
@@ -501,10 +2477,19 @@ func (v *Visitor) VisitStringDataType(ctx *StringDataTypeContext) interface{} {
 		length = WithTrimBracket(length)
 		intLen, err := strconv.Atoi(length)
 		if err != nil {
-			slog.Warn("parse string length error", err)
+			slog.Warn("VisitStringDataType", "parse string length error", err)
 		}
 		res.Length = intLen
 	}
+	if len(ctx.AllBINARY()) != 0 {
+		res.IsBinary = true
+	}
+	if nameCtx := ctx.CharsetName(); nameCtx != nil {
+		res.CharsetName = WithTrimQuote(nameCtx.GetText())
+	}
+	if nameCtx := ctx.CollationName(); nameCtx != nil {
+		res.CollationName = WithTrimQuote(nameCtx.GetText())
+	}
 
 	return &res
 }
@@ -685,7 +2670,7 @@ func (v *Visitor) VisitCollectionDataType(ctx *CollectionDataTypeContext) interf
 			for _, ter := range ops {
 				terWithQuote := ter.GetSymbol().GetText()
 				res.Source += terWithQuote + ","
-				terText := WithTrimQuote(terWithQuote)
+				terText := WithUnquote(terWithQuote)
 				tmp = append(tmp, terText)
 			}
 			res.Source = res.Source[:len(res.Source)-1] + ")"
@@ -2,6 +2,7 @@ package sqlparser
 
 import (
 	"testing"
+	"time"
 
 	"github.com/antlr4-go/antlr/v4"
 	. "github.com/smartystreets/goconvey/convey"
@@ -39,7 +40,28 @@ func TestVisitTableConstraint(t *testing.T) {
 		})
 
 		Convey("TestVisitTableConstraint - FOREIGN", func() {
-			// TODO:
+			str := "CONSTRAINT `fk_user` FOREIGN KEY (`user_id`) REFERENCES `user` (`id`) ON DELETE SET NULL ON UPDATE CASCADE"
+			p := prepare(str)
+
+			res := v.VisitTableConstraint(p.TableConstraint())
+			So(res, ShouldResemble, &TableConstraint{
+				ColumnForeignKey: []string{"user_id"},
+				Name:             "fk_user",
+				Reference: &Reference{
+					Table:    &TableName{Name: "user"},
+					Columns:  []string{"id"},
+					OnDelete: "SET NULL",
+					OnUpdate: "CASCADE",
+				},
+			})
+		})
+
+		Convey("TestVisitTableConstraint - CHECK", func() {
+			str := "CONSTRAINT `chk_age` CHECK (age >= 0)"
+			p := prepare(str)
+
+			res := v.VisitTableConstraint(p.TableConstraint())
+			So(res, ShouldResemble, &TableConstraint{Name: "chk_age", Check: "age >= 0"})
 		})
 	})
 }
@@ -51,6 +73,7 @@ func TestOnlyTableName(t *testing.T) {
 	}{
 		{"foo", "foo"},
 		{"foo`.`bar", "bar"},
+		{"foo.bar", "bar"},
 	}
 
 	Convey("TestOnlyTableName", t, func() {
@@ -116,6 +139,7 @@ func TestVisitCreateTable(t *testing.T) {
 								DefaultValue: &DefaultValue{
 									Value: "",
 									Is:    true,
+									Kind:  DefaultString,
 								},
 								Comment: "学号",
 							},
@@ -125,11 +149,13 @@ func TestVisitCreateTable(t *testing.T) {
 						Name: "name",
 						ColumnDefinition: &ColumnDefinition{
 							DataType: &DataType{
-								Name:      "VARCHAR",
-								Source:    "VARCHAR(255)",
-								Number:    MySqlLexerVARCHAR,
-								HasLength: true,
-								Length:    255,
+								Name:          "VARCHAR",
+								Source:        "VARCHAR(255)",
+								Number:        MySqlLexerVARCHAR,
+								HasLength:     true,
+								Length:        255,
+								CharsetName:   "utf8mb4",
+								CollationName: "utf8mb4_0900_ai_ci",
 							},
 							ColumnConstraint: &ColumnConstraint{
 								NotNull: false,
@@ -156,6 +182,7 @@ func TestVisitCreateTable(t *testing.T) {
 								DefaultValue: &DefaultValue{
 									Value: "",
 									Is:    true,
+									Kind:  DefaultString,
 								},
 								Comment: "用户密码",
 							},
@@ -203,9 +230,11 @@ func TestVisitCreateTable(t *testing.T) {
 							},
 							ColumnConstraint: &ColumnConstraint{
 								DefaultValue: &DefaultValue{
-									Value: "CURRENT_TIMESTAMPONUPDATECURRENT_TIMESTAMP",
+									Value: "CURRENT_TIMESTAMP",
 									Is:    true,
+									Kind:  DefaultExpression,
 								},
+								OnUpdate: "CURRENT_TIMESTAMP",
 							},
 						},
 					},
@@ -216,11 +245,19 @@ func TestVisitCreateTable(t *testing.T) {
 					},
 					{
 						ColumnUniqueKey: []string{"number"},
+						Name:            "number_unique",
 					},
 					{
 						ColumnUniqueKey: []string{"number"},
+						Name:            "number_unique2",
 					},
 				},
+				Options: &TableOptions{
+					Engine:        "InnoDB",
+					AutoIncrement: "8",
+					Charset:       "utf8mb4",
+					Collation:     "utf8mb4_0900_ai_ci",
+				},
 			})
 		})
 
@@ -273,6 +310,7 @@ func TestVisitColumnDefinition(t *testing.T) {
 				DefaultValue: &DefaultValue{
 					Value: "test default",
 					Is:    true,
+					Kind:  DefaultString,
 				},
 				Primary: true,
 				Comment: "test comment",
@@ -358,10 +396,44 @@ func TestVisitColumnDefinition(t *testing.T) {
 				DefaultValue: &DefaultValue{
 					Value: "",
 					Is:    true,
+					Kind:  DefaultString,
 				},
 			})
 		})
 
+		Convey("ON UPDATE", func() {
+			p := prepare("timestamp(3) NULL ON UPDATE CURRENT_TIMESTAMP(3)")
+			res := v.VisitColumnDefinition(p.ColumnDefinition().(*ColumnDefinitionContext)).(*ColumnDefinition)
+			So(res.ColumnConstraint, ShouldResemble, &ColumnConstraint{OnUpdate: "CURRENT_TIMESTAMP(3)"})
+
+			p = prepare("timestamp DEFAULT now() ON UPDATE now()")
+			res = v.VisitColumnDefinition(p.ColumnDefinition().(*ColumnDefinitionContext)).(*ColumnDefinition)
+			So(res.ColumnConstraint, ShouldResemble, &ColumnConstraint{
+				DefaultValue: &DefaultValue{Value: "now()", Is: true, Kind: DefaultExpression},
+				OnUpdate:     "now()",
+			})
+		})
+
+		Convey("DEFAULT kinds", func() {
+			testData := map[string]*DefaultValue{
+				`varchar(3) DEFAULT '007'`:           {Value: "007", Is: true, Kind: DefaultString},
+				`varchar(3) DEFAULT _utf8mb4'it''s'`: {Value: "it's", Is: true, Kind: DefaultString},
+				`int DEFAULT -1`:                     {Value: "-1", Is: true, Kind: DefaultNumber},
+				`decimal(5,2) DEFAULT 1.50`:          {Value: "1.50", Is: true, Kind: DefaultNumber},
+				`bool DEFAULT TRUE`:                  {Value: "1", Is: true, Kind: DefaultNumber},
+				`bit(1) DEFAULT b'0'`:                {Value: "b'0'", Is: true, Kind: DefaultBit},
+				`varbinary(2) DEFAULT 0x0A`:          {Value: "0x0A", Is: true, Kind: DefaultHex},
+				`varbinary(2) DEFAULT X'0A'`:         {Value: "X'0A'", Is: true, Kind: DefaultHex},
+				`char(36) DEFAULT (uuid())`:          {Value: "(uuid())", Is: true, Kind: DefaultExpression},
+				`datetime DEFAULT CURRENT_TIMESTAMP`: {Value: "CURRENT_TIMESTAMP", Is: true, Kind: DefaultExpression},
+				`int DEFAULT NULL`:                   {},
+			}
+			for str, expected := range testData {
+				p := prepare(str)
+				res := v.VisitColumnDefinition(p.ColumnDefinition().(*ColumnDefinitionContext)).(*ColumnDefinition)
+				So(res.ColumnConstraint.DefaultValue, ShouldResemble, expected)
+			}
+		})
 	})
 }
 
@@ -1465,3 +1537,729 @@ func TestVisitDataType(t *testing.T) {
 
 	})
 }
+
+func TestVisitDropRenameTruncateTable(t *testing.T) {
+	Convey("TestVisitDropRenameTruncateTable", t, func() {
+		v := new(Visitor)
+
+		Convey("DropTable", func() {
+			p := prepare("DROP TEMPORARY TABLE IF EXISTS `db`.`t1`, t2 CASCADE")
+			res := v.VisitDropTable(p.DropTable().(*DropTableContext))
+			So(res, ShouldResemble, &DropTable{
+				Temporary: true,
+				IfExists:  true,
+				Tables: []*TableName{
+					{Schema: "db", Name: "t1"},
+					{Name: "t2"},
+				},
+				Cascade: true,
+			})
+		})
+
+		Convey("RenameTable", func() {
+			p := prepare("RENAME TABLE old_a TO new_a, db.old_b TO db.new_b")
+			res := v.VisitRenameTable(p.RenameTable().(*RenameTableContext))
+			So(res, ShouldResemble, &RenameTable{
+				Clauses: []*RenameTableClause{
+					{From: &TableName{Name: "old_a"}, To: &TableName{Name: "new_a"}},
+					{From: &TableName{Schema: "db", Name: "old_b"}, To: &TableName{Schema: "db", Name: "new_b"}},
+				},
+			})
+		})
+
+		Convey("TruncateTable", func() {
+			p := prepare("TRUNCATE `logs`")
+			res := v.VisitTruncateTable(p.TruncateTable().(*TruncateTableContext))
+			So(res, ShouldResemble, &TruncateTable{Table: &TableName{Name: "logs"}})
+		})
+
+		Convey("Statements", func() {
+			res, err := Statements("CREATE TABLE a (id int); RENAME TABLE a TO b; DROP TABLE b;")
+			So(err, ShouldBeNil)
+			So(len(res), ShouldEqual, 3)
+			So(res[0], ShouldHaveSameTypeAs, &CreateTable{})
+			So(res[1], ShouldHaveSameTypeAs, &RenameTable{})
+			So(res[2], ShouldHaveSameTypeAs, &DropTable{})
+		})
+
+		Convey("VisitRoot", func() {
+			p := prepare("CREATE TABLE a (id int); RENAME TABLE a TO b; CREATE TABLE c (id int);")
+			res := v.VisitRoot(p.Root().(*RootContext))
+			So(res, ShouldHaveSameTypeAs, []*CreateTable{})
+			tables := res.([]*CreateTable)
+			So(tables, ShouldHaveLength, 2)
+			So(tables[0].Name, ShouldEqual, "a")
+			So(tables[1].Name, ShouldEqual, "c")
+		})
+	})
+}
+
+func TestVisitDatabase(t *testing.T) {
+	Convey("TestVisitDatabase", t, func() {
+		v := new(Visitor)
+
+		Convey("CreateDatabase", func() {
+			p := prepare("CREATE DATABASE IF NOT EXISTS `shop` DEFAULT CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci ENCRYPTION='Y'")
+			res := v.VisitCreateDatabase(p.CreateDatabase().(*CreateDatabaseContext))
+			So(res, ShouldResemble, &CreateDatabase{
+				IfNotExists: true,
+				Database: &Database{
+					Name:       "shop",
+					Charset:    "utf8mb4",
+					Collation:  "utf8mb4_general_ci",
+					Encryption: "Y",
+				},
+			})
+		})
+
+		Convey("AlterDatabase", func() {
+			v.database = "shop"
+			p := prepare("ALTER SCHEMA CHARSET = latin1 READ ONLY = 1")
+			res := v.VisitAlterSimpleDatabase(p.AlterDatabase().(*AlterSimpleDatabaseContext))
+			So(res, ShouldResemble, &AlterDatabase{
+				Database: &Database{
+					Name:     "shop",
+					Charset:  "latin1",
+					ReadOnly: "1",
+				},
+			})
+		})
+
+		Convey("DropDatabase", func() {
+			v.database = "shop"
+			p := prepare("DROP DATABASE IF EXISTS shop")
+			res := v.VisitDropDatabase(p.DropDatabase().(*DropDatabaseContext))
+			So(res, ShouldResemble, &DropDatabase{IfExists: true, Name: "shop"})
+			So(v.database, ShouldEqual, "")
+		})
+
+		Convey("UseStatement", func() {
+			tbls, err := From("CREATE TABLE a (id int); USE `shop`; CREATE TABLE b (id int); CREATE TABLE other.c (id int);")
+			So(err, ShouldBeNil)
+			So(len(tbls), ShouldEqual, 3)
+			So(tbls[0].Schema, ShouldEqual, "")
+			So(tbls[1].Schema, ShouldEqual, "shop")
+			So(tbls[2].Schema, ShouldEqual, "other")
+			So(tbls[2].Name, ShouldEqual, "c")
+
+			stmts, err := Statements("USE shop; DROP TABLE t1, archive.t2;")
+			So(err, ShouldBeNil)
+			So(stmts[0], ShouldResemble, &UseDatabase{Name: "shop"})
+			So(stmts[1].(*DropTable).Tables, ShouldResemble, []*TableName{
+				{Schema: "shop", Name: "t1"},
+				{Schema: "archive", Name: "t2"},
+			})
+		})
+	})
+}
+
+func TestVisitView(t *testing.T) {
+	Convey("TestVisitView", t, func() {
+		v := new(Visitor)
+
+		Convey("CreateView", func() {
+			str := "CREATE OR REPLACE ALGORITHM=MERGE DEFINER=`admin`@`%` SQL SECURITY INVOKER VIEW report.v_orders (id, total) AS\n" +
+				"WITH recent AS (SELECT * FROM orders WHERE created > NOW() - INTERVAL 1 DAY)\n" +
+				"SELECT r.id, SUM(i.price) FROM recent r JOIN shop.items i ON i.order_id = r.id GROUP BY r.id WITH LOCAL CHECK OPTION"
+			p := prepare(str)
+			res := v.VisitCreateView(p.CreateView().(*CreateViewContext))
+			So(res, ShouldResemble, &CreateView{
+				OrReplace: true,
+				View: &View{
+					Name:        &TableName{Schema: "report", Name: "v_orders"},
+					Algorithm:   "MERGE",
+					Definer:     "admin@%",
+					SQLSecurity: "INVOKER",
+					Columns:     []string{"id", "total"},
+					Select: "WITH recent AS (SELECT * FROM orders WHERE created > NOW() - INTERVAL 1 DAY)\n" +
+						"SELECT r.id, SUM(i.price) FROM recent r JOIN shop.items i ON i.order_id = r.id GROUP BY r.id",
+					WithCheckOption: true,
+					CheckOption:     "LOCAL",
+					Tables: []*TableName{
+						{Name: "orders"},
+						{Schema: "shop", Name: "items"},
+					},
+				},
+			})
+		})
+
+		Convey("AlterView", func() {
+			p := prepare("ALTER VIEW v1 AS SELECT a FROM t1 WHERE b IN (SELECT b FROM t2) WITH CHECK OPTION")
+			res := v.VisitAlterView(p.AlterView().(*AlterViewContext))
+			So(res, ShouldResemble, &AlterView{
+				View: &View{
+					Name:            &TableName{Name: "v1"},
+					Select:          "SELECT a FROM t1 WHERE b IN (SELECT b FROM t2)",
+					WithCheckOption: true,
+					Tables:          []*TableName{{Name: "t1"}, {Name: "t2"}},
+				},
+			})
+		})
+
+		Convey("DropView", func() {
+			p := prepare("DROP VIEW IF EXISTS v1, report.v2")
+			res := v.VisitDropView(p.DropView().(*DropViewContext))
+			So(res, ShouldResemble, &DropView{
+				IfExists: true,
+				Views:    []*TableName{{Name: "v1"}, {Schema: "report", Name: "v2"}},
+			})
+		})
+	})
+}
+
+func TestVisitTrigger(t *testing.T) {
+	Convey("TestVisitTrigger", t, func() {
+		v := new(Visitor)
+
+		Convey("CreateTrigger", func() {
+			str := "CREATE DEFINER=CURRENT_USER TRIGGER shop.orders_ai AFTER INSERT ON orders FOR EACH ROW FOLLOWS orders_audit\n" +
+				"BEGIN\n" +
+				"  DECLARE n INT;\n" +
+				"  SELECT COUNT(*) INTO n FROM order_items WHERE order_id = NEW.id;\n" +
+				"  UPDATE stats SET orders = orders + 1, items = items + n;\n" +
+				"  INSERT INTO audit_log (msg) VALUES ('order');\n" +
+				"END"
+			p := prepare(str)
+			res := v.VisitCreateTrigger(p.CreateTrigger().(*CreateTriggerContext))
+			So(res, ShouldResemble, &CreateTrigger{
+				Trigger: &Trigger{
+					Name:         &TableName{Schema: "shop", Name: "orders_ai"},
+					Definer:      "CURRENT_USER",
+					Time:         "AFTER",
+					Event:        "INSERT",
+					Table:        &TableName{Name: "orders"},
+					Order:        "FOLLOWS",
+					OtherTrigger: &TableName{Name: "orders_audit"},
+					Body: &RoutineBody{
+						Span: Span{Start: 108, Stop: 307, Line: 2, Column: 0},
+						Source: "BEGIN\n" +
+							"  DECLARE n INT;\n" +
+							"  SELECT COUNT(*) INTO n FROM order_items WHERE order_id = NEW.id;\n" +
+							"  UPDATE stats SET orders = orders + 1, items = items + n;\n" +
+							"  INSERT INTO audit_log (msg) VALUES ('order');\n" +
+							"END",
+						Statements: []string{
+							"SELECT COUNT(*) INTO n FROM order_items WHERE order_id = NEW.id",
+							"UPDATE stats SET orders = orders + 1, items = items + n",
+							"INSERT INTO audit_log (msg) VALUES ('order')",
+						},
+						Tables: []*TableName{{Name: "order_items"}, {Name: "stats"}, {Name: "audit_log"}},
+						Writes: []*TableName{{Name: "stats"}, {Name: "audit_log"}},
+					},
+				},
+			})
+		})
+
+		Convey("CreateTrigger - single statement", func() {
+			p := prepare("CREATE TRIGGER t_bu BEFORE UPDATE ON t FOR EACH ROW SET NEW.updated = NOW()")
+			res := v.VisitCreateTrigger(p.CreateTrigger().(*CreateTriggerContext)).(*CreateTrigger)
+			So(res.Trigger.Time, ShouldEqual, "BEFORE")
+			So(res.Trigger.Event, ShouldEqual, "UPDATE")
+			So(res.Trigger.Body.Statements, ShouldResemble, []string{"SET NEW.updated = NOW()"})
+			So(res.Trigger.Body.Tables, ShouldBeNil)
+		})
+
+		Convey("DropTrigger", func() {
+			p := prepare("DROP TRIGGER IF EXISTS shop.orders_ai")
+			res := v.VisitDropTrigger(p.DropTrigger().(*DropTriggerContext))
+			So(res, ShouldResemble, &DropTrigger{IfExists: true, Name: &TableName{Schema: "shop", Name: "orders_ai"}})
+		})
+	})
+}
+
+func TestVisitRoutine(t *testing.T) {
+	Convey("TestVisitRoutine", t, func() {
+		v := new(Visitor)
+
+		Convey("CreateProcedure", func() {
+			str := "CREATE DEFINER=`root`@`localhost` PROCEDURE shop.add_order(IN p_user BIGINT UNSIGNED, OUT p_id INT, INOUT p_note VARCHAR(64))\n" +
+				"COMMENT 'add an order' MODIFIES SQL DATA SQL SECURITY INVOKER\n" +
+				"BEGIN\n" +
+				"  INSERT INTO orders (user_id, note) VALUES (p_user, p_note);\n" +
+				"  SET p_id = LAST_INSERT_ID();\n" +
+				"END"
+			p := prepare(str)
+			res := v.VisitCreateProcedure(p.CreateProcedure().(*CreateProcedureContext)).(*CreateProcedure)
+			proc := res.Procedure
+			So(proc.Name, ShouldResemble, &TableName{Schema: "shop", Name: "add_order"})
+			So(proc.Definer, ShouldEqual, "root@localhost")
+			So(proc.Parameters, ShouldResemble, []*RoutineParameter{
+				{
+					Direction: "IN",
+					Name:      "p_user",
+					DataType: &DataType{
+						Name:       "BIGINT",
+						Number:     MySqlLexerBIGINT,
+						Source:     "BIGINT UNSIGNED",
+						IsUnsigned: true,
+					},
+				},
+				{
+					Direction: "OUT",
+					Name:      "p_id",
+					DataType:  &DataType{Name: "INT", Number: MySqlLexerINT, Source: "INT"},
+				},
+				{
+					Direction: "INOUT",
+					Name:      "p_note",
+					DataType: &DataType{
+						Name:      "VARCHAR",
+						Number:    MySqlLexerVARCHAR,
+						Source:    "VARCHAR(64)",
+						HasLength: true,
+						Length:    64,
+					},
+				},
+			})
+			So(proc.Characteristics, ShouldResemble, &RoutineCharacteristics{
+				Comment:     "add an order",
+				DataAccess:  "MODIFIES SQL DATA",
+				SQLSecurity: "INVOKER",
+			})
+			So(proc.Body.Span.Line, ShouldEqual, 3)
+			So(proc.Body.Statements, ShouldResemble, []string{
+				"INSERT INTO orders (user_id, note) VALUES (p_user, p_note)",
+				"SET p_id = LAST_INSERT_ID()",
+			})
+			So(proc.Body.Writes, ShouldResemble, []*TableName{{Name: "orders"}})
+		})
+
+		Convey("CreateFunction", func() {
+			str := "CREATE FUNCTION IF NOT EXISTS order_total(p_id INT) RETURNS DECIMAL(10,2) DETERMINISTIC READS SQL DATA\n" +
+				"RETURN (SELECT SUM(price) FROM order_items WHERE order_id = p_id)"
+			p := prepare(str)
+			res := v.VisitCreateFunction(p.CreateFunction().(*CreateFunctionContext)).(*CreateFunction)
+			So(res.IfNotExists, ShouldBeTrue)
+			fn := res.Function
+			So(fn.Name, ShouldResemble, &TableName{Name: "order_total"})
+			So(fn.Parameters, ShouldResemble, []*RoutineParameter{
+				{Name: "p_id", DataType: &DataType{Name: "INT", Number: MySqlLexerINT, Source: "INT"}},
+			})
+			So(fn.Returns, ShouldResemble, &DataType{
+				Name:         "DECIMAL",
+				Number:       MySqlLexerDECIMAL,
+				Source:       "DECIMAL(10,2)",
+				HasTwoLength: true,
+				Len1:         10,
+				Len2:         2,
+			})
+			So(fn.Characteristics, ShouldResemble, &RoutineCharacteristics{
+				Deterministic: true,
+				DataAccess:    "READS SQL DATA",
+			})
+			So(fn.Body.Source, ShouldEqual, "RETURN (SELECT SUM(price) FROM order_items WHERE order_id = p_id)")
+			So(fn.Body.Tables, ShouldResemble, []*TableName{{Name: "order_items"}})
+		})
+
+		Convey("DropProcedure and DropFunction", func() {
+			p := prepare("DROP PROCEDURE IF EXISTS shop.add_order")
+			So(v.VisitDropProcedure(p.DropProcedure().(*DropProcedureContext)), ShouldResemble, &DropProcedure{
+				IfExists: true,
+				Name:     &TableName{Schema: "shop", Name: "add_order"},
+			})
+			p = prepare("DROP FUNCTION order_total")
+			So(v.VisitDropFunction(p.DropFunction().(*DropFunctionContext)), ShouldResemble, &DropFunction{
+				Name: &TableName{Name: "order_total"},
+			})
+		})
+	})
+}
+
+func TestVisitEvent(t *testing.T) {
+	Convey("TestVisitEvent", t, func() {
+		v := new(Visitor)
+
+		Convey("CreateEvent - EVERY", func() {
+			str := "CREATE EVENT IF NOT EXISTS shop.cleanup ON SCHEDULE EVERY 1 DAY STARTS '2024-01-01 03:00:00' + INTERVAL 30 MINUTE ENDS '2025-01-01'\n" +
+				"ON COMPLETION PRESERVE DISABLE COMMENT 'purge old sessions'\n" +
+				"DO DELETE FROM sessions WHERE expired_at < NOW()"
+			p := prepare(str)
+			res := v.VisitCreateEvent(p.CreateEvent().(*CreateEventContext)).(*CreateEvent)
+			So(res.IfNotExists, ShouldBeTrue)
+			event := res.Event
+			So(event.Name, ShouldResemble, &TableName{Schema: "shop", Name: "cleanup"})
+			So(event.Schedule, ShouldResemble, &Schedule{
+				Every: &Interval{Value: "1", Unit: "DAY"},
+				Starts: &EventTimestamp{
+					Value:     "2024-01-01 03:00:00",
+					Time:      time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC),
+					Intervals: []*Interval{{Value: "30", Unit: "MINUTE"}},
+				},
+				Ends: &EventTimestamp{
+					Value: "2025-01-01",
+					Time:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+				},
+			})
+			So(event.OnCompletion, ShouldEqual, "PRESERVE")
+			So(event.Status, ShouldEqual, "DISABLE")
+			So(event.Comment, ShouldEqual, "purge old sessions")
+			So(event.Body.Statements, ShouldResemble, []string{"DELETE FROM sessions WHERE expired_at < NOW()"})
+			So(event.Body.Writes, ShouldResemble, []*TableName{{Name: "sessions"}})
+		})
+
+		Convey("CreateEvent - AT", func() {
+			p := prepare("CREATE DEFINER=CURRENT_USER EVENT once ON SCHEDULE AT CURRENT_TIMESTAMP + INTERVAL '1:30' HOUR_MINUTE ON COMPLETION NOT PRESERVE DO TRUNCATE tmp")
+			res := v.VisitCreateEvent(p.CreateEvent().(*CreateEventContext)).(*CreateEvent)
+			So(res.Event.Definer, ShouldEqual, "CURRENT_USER")
+			So(res.Event.Schedule, ShouldResemble, &Schedule{
+				At: &EventTimestamp{
+					Value:     "CURRENT_TIMESTAMP",
+					Intervals: []*Interval{{Value: "1:30", Unit: "HOUR_MINUTE"}},
+				},
+			})
+			So(res.Event.OnCompletion, ShouldEqual, "NOT PRESERVE")
+		})
+
+		Convey("AlterEvent", func() {
+			p := prepare("ALTER EVENT cleanup ON SCHEDULE EVERY 12 HOUR RENAME TO shop.purge ENABLE COMMENT 'it''s \"purged\"'")
+			res := v.VisitAlterEvent(p.AlterEvent().(*AlterEventContext))
+			So(res, ShouldResemble, &AlterEvent{
+				Event: &Event{
+					Name:     &TableName{Name: "cleanup"},
+					Schedule: &Schedule{Every: &Interval{Value: "12", Unit: "HOUR"}},
+					Status:   "ENABLE",
+					Comment:  `it's "purged"`,
+				},
+				RenameTo: &TableName{Schema: "shop", Name: "purge"},
+			})
+		})
+
+		Convey("DropEvent", func() {
+			p := prepare("DROP EVENT IF EXISTS cleanup")
+			res := v.VisitDropEvent(p.DropEvent().(*DropEventContext))
+			So(res, ShouldResemble, &DropEvent{IfExists: true, Name: &TableName{Name: "cleanup"}})
+		})
+	})
+}
+
+func TestVisitAccount(t *testing.T) {
+	Convey("TestVisitAccount", t, func() {
+		v := new(Visitor)
+
+		Convey("CreateUser", func() {
+			p := prepare("CREATE USER IF NOT EXISTS 'app'@'%' IDENTIFIED BY 'secret', `report`@`10.0.0.1` DEFAULT ROLE reader")
+			res := v.VisitCreateUser(p.CreateUser())
+			So(res, ShouldResemble, &CreateUser{
+				IfNotExists:  true,
+				Users:        []*Principal{{User: "app", Host: "%"}, {User: "report", Host: "10.0.0.1"}},
+				DefaultRoles: []*Principal{{User: "reader"}},
+			})
+		})
+
+		Convey("CreateRole and DropUser", func() {
+			p := prepare("CREATE ROLE IF NOT EXISTS reader, 'writer'@'%'")
+			So(v.VisitCreateRole(p.CreateRole().(*CreateRoleContext)), ShouldResemble, &CreateRole{
+				IfNotExists: true,
+				Roles:       []*Principal{{User: "reader"}, {User: "writer", Host: "%"}},
+			})
+			p = prepare("DROP USER 'app'@'%'")
+			So(v.VisitDropUser(p.DropUser().(*DropUserContext)), ShouldResemble, &DropUser{
+				Users: []*Principal{{User: "app", Host: "%"}},
+			})
+		})
+
+		Convey("Grant privileges", func() {
+			p := prepare("GRANT SELECT, INSERT (name, email), CREATE TEMPORARY TABLES ON shop.users TO 'app'@'%' WITH GRANT OPTION")
+			res := v.VisitGrantStatement(p.GrantStatement().(*GrantStatementContext))
+			So(res, ShouldResemble, &Grant{
+				Privileges: []*Privilege{
+					{Type: "SELECT"},
+					{Type: "INSERT", Columns: []string{"name", "email"}},
+					{Type: "CREATE TEMPORARY TABLES"},
+				},
+				Object:          &PrivilegeObject{Scope: ScopeTable, Schema: "shop", Name: "users"},
+				To:              []*Principal{{User: "app", Host: "%"}},
+				WithGrantOption: true,
+			})
+
+			p = prepare("GRANT ALL PRIVILEGES ON *.* TO admin")
+			res = v.VisitGrantStatement(p.GrantStatement().(*GrantStatementContext))
+			So(res.(*Grant).Object, ShouldResemble, &PrivilegeObject{Scope: ScopeGlobal})
+			So(res.(*Grant).WithGrantOption, ShouldBeFalse)
+
+			v.database = "shop"
+			p = prepare("GRANT EXECUTE ON PROCEDURE add_order TO app")
+			res = v.VisitGrantStatement(p.GrantStatement().(*GrantStatementContext))
+			So(res.(*Grant).Object, ShouldResemble, &PrivilegeObject{
+				Scope:      ScopeRoutine,
+				ObjectType: "PROCEDURE",
+				Schema:     "shop",
+				Name:       "add_order",
+			})
+
+			p = prepare("GRANT SELECT ON `shop`.* TO app")
+			res = v.VisitGrantStatement(p.GrantStatement().(*GrantStatementContext))
+			So(res.(*Grant).Object, ShouldResemble, &PrivilegeObject{Scope: ScopeDatabase, Schema: "shop"})
+		})
+
+		Convey("Grant roles", func() {
+			p := prepare("GRANT reader, writer TO 'app'@'%', report WITH ADMIN OPTION")
+			res := v.VisitGrantStatement(p.GrantStatement().(*GrantStatementContext))
+			So(res, ShouldResemble, &GrantRole{
+				Roles:           []*Principal{{User: "reader"}, {User: "writer"}},
+				To:              []*Principal{{User: "app", Host: "%"}, {User: "report"}},
+				WithAdminOption: true,
+			})
+		})
+
+		Convey("Revoke", func() {
+			p := prepare("REVOKE UPDATE ON shop.* FROM 'app'@'%'")
+			So(v.VisitRevokeStatement(p.RevokeStatement()), ShouldResemble, &Revoke{
+				Privileges: []*Privilege{{Type: "UPDATE"}},
+				Object:     &PrivilegeObject{Scope: ScopeDatabase, Schema: "shop"},
+				From:       []*Principal{{User: "app", Host: "%"}},
+			})
+			p = prepare("REVOKE ALL PRIVILEGES, GRANT OPTION FROM app")
+			So(v.VisitRevokeStatement(p.RevokeStatement()), ShouldResemble, &Revoke{
+				Privileges: []*Privilege{{Type: "ALL PRIVILEGES"}, {Type: "GRANT OPTION"}},
+				From:       []*Principal{{User: "app"}},
+			})
+			p = prepare("REVOKE writer FROM app")
+			So(v.VisitRevokeStatement(p.RevokeStatement()), ShouldResemble, &RevokeRole{
+				Roles: []*Principal{{User: "writer"}},
+				From:  []*Principal{{User: "app"}},
+			})
+		})
+
+		Convey("SetRole", func() {
+			p := prepare("SET ROLE ALL EXCEPT writer")
+			So(v.VisitSetRole(p.SetRole().(*SetRoleContext)), ShouldResemble, &SetRole{
+				Option: "ALL",
+				Except: []*Principal{{User: "writer"}},
+			})
+			p = prepare("SET DEFAULT ROLE reader, writer TO app")
+			So(v.VisitSetRole(p.SetRole().(*SetRoleContext)), ShouldResemble, &SetRole{
+				Default: true,
+				Roles:   []*Principal{{User: "reader"}, {User: "writer"}},
+				To:      []*Principal{{User: "app"}},
+			})
+		})
+
+		Convey("Statements", func() {
+			stmts, err := Statements("CREATE ROLE reader; GRANT SELECT ON shop.* TO reader; GRANT reader TO app;")
+			So(err, ShouldBeNil)
+			So(len(stmts), ShouldEqual, 3)
+			So(stmts[1], ShouldHaveSameTypeAs, &Grant{})
+			So(stmts[2], ShouldHaveSameTypeAs, &GrantRole{})
+		})
+	})
+}
+
+func TestVisitSetStatement(t *testing.T) {
+	Convey("TestVisitSetStatement", t, func() {
+		v := new(Visitor)
+
+		Convey("SetVariable", func() {
+			p := prepare("SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='ANSI_QUOTES,NO_AUTO_VALUE_ON_ZERO', @@GLOBAL.max_connections = 100, SESSION foreign_key_checks = ON")
+			res := v.VisitSetStatement(p.SetStatement())
+			So(res, ShouldResemble, &SetVariable{
+				Assignments: []*VariableAssignment{
+					{Scope: VariableUser, Name: "OLD_SQL_MODE", Value: "@@SQL_MODE"},
+					{Scope: VariableSession, Name: "SQL_MODE", Value: "'ANSI_QUOTES,NO_AUTO_VALUE_ON_ZERO'"},
+					{Scope: VariableGlobal, Name: "max_connections", Value: "100"},
+					{Scope: VariableSession, Name: "foreign_key_checks", Value: "ON"},
+				},
+			})
+			So(v.session.SQLMode, ShouldEqual, "ANSI_QUOTES,NO_AUTO_VALUE_ON_ZERO")
+			So(v.session.ANSIQuotes(), ShouldBeTrue)
+
+			p = prepare("SET SQL_MODE=@OLD_SQL_MODE")
+			v.VisitSetStatement(p.SetStatement())
+			So(v.session.SQLMode, ShouldEqual, "")
+			So(v.session.ANSIQuotes(), ShouldBeFalse)
+		})
+
+		Convey("SetNames", func() {
+			p := prepare("SET NAMES utf8mb4 COLLATE utf8mb4_unicode_ci")
+			So(v.VisitSetStatement(p.SetStatement()), ShouldResemble, &SetNames{
+				Charset:   "utf8mb4",
+				Collation: "utf8mb4_unicode_ci",
+			})
+			So(v.session, ShouldResemble, &Session{
+				CharacterSetClient:  "utf8mb4",
+				CollationConnection: "utf8mb4_unicode_ci",
+			})
+		})
+
+		Convey("mysqldump header", func() {
+			str := "/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;\n" +
+				"/*!50503 SET NAMES utf8mb4 */;\n" +
+				"/*!40101 SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='ANSI' */;\n" +
+				"CREATE TABLE \"a\" (\"id\" int);\n" +
+				"/*!40101 SET SQL_MODE=@OLD_SQL_MODE */;\n" +
+				"/*!40101 SET CHARACTER_SET_CLIENT=@OLD_CHARACTER_SET_CLIENT */;\n" +
+				"CREATE TABLE b (id int);"
+			stmts, err := Statements(str)
+			So(err, ShouldBeNil)
+			So(len(stmts), ShouldEqual, 7)
+			So(stmts[1], ShouldResemble, &SetNames{Charset: "utf8mb4"})
+
+			first := stmts[3].(*CreateTable)
+			So(first.Name, ShouldEqual, "a")
+			So(first.Columns[0].Name, ShouldEqual, "id")
+			So(first.Session, ShouldResemble, &Session{SQLMode: "ANSI", CharacterSetClient: "utf8mb4"})
+			So(first.Session.ANSIQuotes(), ShouldBeTrue)
+
+			second := stmts[6].(*CreateTable)
+			So(second.Session, ShouldResemble, &Session{})
+		})
+
+		Convey("Executable comments", func() {
+			str := "/*!40101 SET NAMES utf8 */; /*!80000 SET NAMES latin1 */;\n" +
+				"CREATE TABLE a (id int COMMENT 'x /*!50000 y*/', `/*!z*/` int) /* /*!40101 no */; -- /*!40101 no */\n" +
+				"/*!SET NAMES utf8mb4*/;"
+			So(WithExecutableComments(str, "5.7.44"), ShouldEqual,
+				"         SET NAMES utf8   ; /*!80000 SET NAMES latin1 */;\n"+
+					"CREATE TABLE a (id int COMMENT 'x /*!50000 y*/', `/*!z*/` int) /* /*!40101 no */; -- /*!40101 no */\n"+
+					"   SET NAMES utf8mb4  ;")
+			So(WithExecutableComments(str, ""), ShouldStartWith, "         SET NAMES utf8   ;          SET NAMES latin1   ;")
+
+			stmts, err := Statements(str)
+			So(err, ShouldBeNil)
+			So(stmts, ShouldHaveLength, 4)
+			table := stmts[2].(*CreateTable)
+			So(table.Columns[0].ColumnDefinition.ColumnConstraint.Comment, ShouldEqual, "x /*!50000 y*/")
+			So(table.Columns[1].Name, ShouldEqual, "/*!z*/")
+		})
+
+		Convey("ANSI_QUOTES identifiers", func() {
+			str := "CREATE TABLE \"a\"\"b\" (id int);\n" +
+				"SET sql_mode = 'ANSI_QUOTES';\n" +
+				"CREATE TABLE \"shop\".\"my\"\"t\" (\"a\"\"b\" int, `c``d` int, KEY (\"a\"\"b\"));\n" +
+				"ALTER TABLE \"my\"\"t\" DROP COLUMN \"c`d\";"
+			stmts, err := Statements(str)
+			So(err, ShouldBeNil)
+			So(stmts, ShouldHaveLength, 4)
+			// the double quotes are the string quotes which are trimmed
+			So(stmts[0].(*CreateTable).Name, ShouldEqual, `a""b`)
+
+			table := stmts[2].(*CreateTable)
+			So(table.Schema, ShouldEqual, "shop")
+			So(table.Name, ShouldEqual, `my"t`)
+			So(table.Columns[0].Name, ShouldEqual, `a"b`)
+			So(table.Columns[1].Name, ShouldEqual, "c`d")
+			So(table.Constraints[0].ColumnIndex, ShouldResemble, []string{`a"b`})
+
+			alter := stmts[3].(*AlterTable)
+			So(alter.Table, ShouldResemble, &TableName{Name: `my"t`})
+			So(alter.Specs[0].Name, ShouldEqual, "c`d")
+		})
+	})
+}
+
+func TestVisitAlterTable(t *testing.T) {
+	Convey("TestVisitAlterTable", t, func() {
+		v := new(Visitor)
+
+		Convey("AlterTable", func() {
+			str := "ALTER TABLE db.user ADD COLUMN age int NOT NULL AFTER name, " +
+				"CHANGE nick nickname varchar(32), RENAME COLUMN a TO b, DROP COLUMN c, " +
+				"ADD UNIQUE KEY uk_email (email), DROP INDEX idx_old, DROP FOREIGN KEY fk_old, " +
+				"ALTER COLUMN status SET DEFAULT 'on', ENGINE=InnoDB COMMENT='users', ALGORITHM=INPLACE, RENAME TO people"
+			p := prepare(str)
+			res := v.VisitAlterTable(p.AlterTable().(*AlterTableContext)).(*AlterTable)
+
+			So(res.Table, ShouldResemble, &TableName{Schema: "db", Name: "user"})
+			var actions []string
+			for _, spec := range res.Specs {
+				actions = append(actions, spec.Action)
+			}
+			So(actions, ShouldResemble, []string{
+				AlterAddColumn, AlterChangeColumn, AlterRenameColumn, AlterDropColumn,
+				AlterAddConstraint, AlterDropIndex, AlterDropForeignKey,
+				AlterSetDefault, AlterTableOptions, AlterAlgorithm, AlterRenameTable,
+			})
+
+			So(res.Specs[0].Column.Name, ShouldEqual, "age")
+			So(res.Specs[0].Column.DataType.Name, ShouldEqual, "INT")
+			So(res.Specs[0].Column.Constraint.NotNull, ShouldBeTrue)
+			So(res.Specs[0].After, ShouldEqual, "name")
+			So(res.Specs[1].Name, ShouldEqual, "nick")
+			So(res.Specs[1].NewName, ShouldEqual, "nickname")
+			So(res.Specs[1].Column.DataType.Source, ShouldEqual, "VARCHAR(32)")
+			So(res.Specs[2].Name, ShouldEqual, "a")
+			So(res.Specs[2].NewName, ShouldEqual, "b")
+			So(res.Specs[3].Name, ShouldEqual, "c")
+			So(res.Specs[4].Constraint, ShouldResemble, &TableConstraint{ColumnUniqueKey: []string{"email"}, Name: "uk_email"})
+			So(res.Specs[5].Name, ShouldEqual, "idx_old")
+			So(res.Specs[6].Name, ShouldEqual, "fk_old")
+			So(res.Specs[7].Default, ShouldResemble, &DefaultValue{Value: "on", Is: true, Kind: DefaultString})
+			So(res.Specs[8].Options, ShouldResemble, &TableOptions{Engine: "InnoDB", Comment: "users"})
+			So(res.Specs[9].Value, ShouldEqual, "INPLACE")
+			So(res.Specs[10].RenameTo, ShouldResemble, &TableName{Name: "people"})
+		})
+
+		Convey("AddDefinitions", func() {
+			p := prepare("ALTER TABLE t ADD (x int, INDEX idx_x (x), FULLTEXT (body))")
+			res := v.VisitAlterTable(p.AlterTable().(*AlterTableContext)).(*AlterTable)
+			So(len(res.Specs), ShouldEqual, 3)
+			So(res.Specs[0].Action, ShouldEqual, AlterAddColumn)
+			So(res.Specs[0].Column.Name, ShouldEqual, "x")
+			So(res.Specs[1].Constraint, ShouldResemble, &TableConstraint{ColumnIndex: []string{"x"}, Name: "idx_x"})
+			So(res.Specs[2].Constraint, ShouldResemble, &TableConstraint{ColumnIndex: []string{"body"}, IndexKind: "FULLTEXT"})
+		})
+
+		Convey("CreateIndex", func() {
+			p := prepare("CREATE UNIQUE INDEX uk_a ON t (a, b DESC) ALGORITHM = INPLACE LOCK = NONE")
+			res := v.VisitCreateIndex(p.CreateIndex().(*CreateIndexContext))
+			So(res, ShouldResemble, &CreateIndex{
+				Table:      &TableName{Name: "t"},
+				Constraint: &TableConstraint{ColumnUniqueKey: []string{"a", "b"}, Name: "uk_a"},
+				Algorithm:  "INPLACE",
+				Lock:       "NONE",
+			})
+		})
+
+		Convey("DropIndex", func() {
+			p := prepare("DROP INDEX `idx_a` ON db.t")
+			res := v.VisitDropIndex(p.DropIndex().(*DropIndexContext))
+			So(res, ShouldResemble, &DropIndex{Name: "idx_a", Table: &TableName{Schema: "db", Name: "t"}})
+		})
+
+		Convey("Partition", func() {
+			p := prepare("ALTER TABLE t PARTITION BY RANGE COLUMNS (a) (PARTITION p0 VALUES LESS THAN (10), PARTITION p1 VALUES LESS THAN MAXVALUE)")
+			res := v.VisitAlterTable(p.AlterTable().(*AlterTableContext)).(*AlterTable)
+			So(len(res.Specs), ShouldEqual, 1)
+			So(res.Specs[0].Action, ShouldEqual, AlterPartitionBy)
+			So(res.Specs[0].Partition, ShouldResemble, &Partitioning{
+				Type:    "RANGE",
+				Columns: []string{"a"},
+				Partitions: []*Partition{
+					{Name: "p0", Source: "PARTITION p0 VALUES LESS THAN (10)"},
+					{Name: "p1", Source: "PARTITION p1 VALUES LESS THAN MAXVALUE"},
+				},
+				Source: "PARTITION BY RANGE COLUMNS (a) (PARTITION p0 VALUES LESS THAN (10), PARTITION p1 VALUES LESS THAN MAXVALUE)",
+			})
+
+			p = prepare("ALTER TABLE t DROP PARTITION p0, p1")
+			res = v.VisitAlterTable(p.AlterTable().(*AlterTableContext)).(*AlterTable)
+			So(len(res.Specs), ShouldEqual, 2)
+			So(res.Specs[1].Action, ShouldEqual, AlterDropPartition)
+			So(res.Specs[1].Name, ShouldEqual, "p1")
+		})
+
+		Convey("Parse", func() {
+			res, err := Parse("USE db;\nALTER TABLE t DROP COLUMN c;")
+			So(err, ShouldBeNil)
+			So(len(res), ShouldEqual, 2)
+			So(res[1].Span, ShouldResemble, Span{Start: 8, Stop: 34, Line: 2, Column: 0})
+			So(res[1].Source, ShouldEqual, "ALTER TABLE t DROP COLUMN c")
+			So(res[1].Node.(*AlterTable).Table, ShouldResemble, &TableName{Schema: "db", Name: "t"})
+		})
+
+		Convey("Syntax errors", func() {
+			str := "CREATE TABLE a (id int);\nALTER TABLE a ADD COLUMN y int,;\nCREATE TABLE b (id int);"
+			_, err := Parse(str)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldStartWith, "line 2:31 ")
+			_, err = Statements(str)
+			So(err, ShouldHaveSameTypeAs, SyntaxErrors{})
+
+			// From is tolerant, the tables before the syntax error are returned
+			tbls, err := From(str)
+			So(err, ShouldBeNil)
+			So(len(tbls), ShouldEqual, 1)
+			So(tbls[0].Name, ShouldEqual, "a")
+		})
+	})
+}